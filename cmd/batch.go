@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/robinovitch61/wander/internal/tui/components/app"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+const (
+	defaultBatchWidth = 120
+	// tall enough to fit every row when the height isn't limited by a terminal
+	untrimmedBatchHeight = 10000
+)
+
+func batchEntrypoint(cmd *cobra.Command) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = defaultBatchWidth, untrimmedBatchHeight
+	}
+
+	snapshot, err := app.InitialModel(getConfig(cmd, "")).Snapshot(width, height)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(trimTrailingBlankLines(snapshot))
+}
+
+func trimTrailingBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for len(lines) > 0 && strings.TrimSpace(formatter.StripANSI(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
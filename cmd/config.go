@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"strings"
+)
+
+var (
+	configDescription = `Prints the resolved configuration, where each connection value came from, and any deprecated options in use.
+Values are taken from a profile, then command line arguments, then environment variables like NOMAD_ADDR, then the
+config file, then defaults.`
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Print resolved configuration",
+		Long:  configDescription,
+		Run:   configEntrypoint,
+	}
+)
+
+func configEntrypoint(cmd *cobra.Command, args []string) {
+	config := getConfig(cmd, "")
+
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		configFile = "none"
+	}
+	token := ""
+	if config.Token != "" {
+		token = "<redacted>"
+	}
+
+	_, profile := retrieveProfile(cmd)
+	tokenSource := valueSource(cmd, tokenArg, profile.Token, oldTokenArg)
+	if tokenSource == "default" && config.TokenFile != "" {
+		tokenSource = "token file " + config.TokenFile
+	}
+
+	fmt.Printf("config file: %s\n", configFile)
+	fmt.Printf("%s: %s (%s)\n", addrArg.cfgFileEnvVar, config.URL, valueSource(cmd, addrArg, profile.Addr, oldAddrArg))
+	fmt.Printf("%s: %s (%s)\n", tokenArg.cfgFileEnvVar, token, tokenSource)
+	fmt.Printf("%s: %s (%s)\n", regionArg.cfgFileEnvVar, config.Region, valueSource(cmd, regionArg, profile.Region))
+	fmt.Printf("%s: %s (%s)\n", namespaceArg.cfgFileEnvVar, config.Namespace, valueSource(cmd, namespaceArg, profile.Namespace))
+	fmt.Printf("%s: %t\n", readOnlyArg.cfgFileEnvVar, config.ReadOnly)
+	fmt.Printf("%s: %s\n", updateSecondsArg.cfgFileEnvVar, config.UpdateSeconds)
+
+	fmt.Println("\nconfig file problems:")
+	if len(configFileProblems) == 0 {
+		fmt.Println("none")
+	}
+	for _, p := range configFileProblems {
+		fmt.Printf("- %s\n", p)
+	}
+
+	fmt.Println("\ndeprecations:")
+	if len(activeDeprecations) == 0 {
+		fmt.Println("none")
+	}
+	for _, d := range activeDeprecations {
+		fmt.Printf("- %s\n", d)
+	}
+}
+
+// valueSource describes where getConfig took a's value from, in order of precedence. Deprecated args are only consulted
+// when nothing else is set.
+func valueSource(cmd *cobra.Command, a arg, profileVal string, deprecatedArgs ...arg) string {
+	if profileVal != "" {
+		return "profile " + retrieveWithDefault(cmd, profileNameArg, "")
+	}
+	if cmd.Flag(a.cliLong).Value.String() != "" {
+		return "--" + a.cliLong + " argument"
+	}
+	for _, c := range append([]arg{a}, deprecatedArgs...) {
+		if os.Getenv(strings.ToUpper(c.cfgFileEnvVar)) != "" {
+			return strings.ToUpper(c.cfgFileEnvVar) + " env variable"
+		}
+		if viper.InConfig(c.cfgFileEnvVar) {
+			return c.cfgFileEnvVar + " in config file"
+		}
+	}
+	return "default"
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// deprecation is an old env variable or config file key in use that will be removed in a future release
+type deprecation struct {
+	Old, Replacement arg
+}
+
+func (d deprecation) String() string {
+	return fmt.Sprintf(
+		"use of %s env variable or %s in config file will be removed in a future release, use %s env variable or %s in config file instead",
+		strings.ToUpper(d.Old.cfgFileEnvVar),
+		d.Old.cfgFileEnvVar,
+		strings.ToUpper(d.Replacement.cfgFileEnvVar),
+		d.Replacement.cfgFileEnvVar,
+	)
+}
+
+// activeDeprecations are collected while resolving config instead of printed, as printing to stdout corrupts piped
+// output and clutters startup. They are shown in the TUI, printed to stderr in headless modes, and listed by
+// `wander config`.
+var activeDeprecations []deprecation
+
+func recordDeprecation(d deprecation) {
+	for _, existing := range activeDeprecations {
+		if existing.Old.cfgFileEnvVar == d.Old.cfgFileEnvVar {
+			return
+		}
+	}
+	activeDeprecations = append(activeDeprecations, d)
+}
+
+func getDeprecationWarnings() []string {
+	var warnings []string
+	for _, d := range activeDeprecations {
+		warnings = append(warnings, "warning: "+d.String())
+	}
+	return warnings
+}
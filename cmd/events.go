@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/nomad/api"
+	"github.com/itchyny/gojq"
+	"github.com/robinovitch61/wander/internal/fileio"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var (
+	eventsDescription = `Streams events as jq-filtered JSON lines to stdout without starting the TUI.`
+
+	eventsCmd = &cobra.Command{
+		Use:   "events",
+		Short: "Stream events as JSON lines",
+		Long:  eventsDescription,
+		Run:   eventsEntrypoint,
+	}
+)
+
+func eventsEntrypoint(cmd *cobra.Command, args []string) {
+	config := getConfig(cmd, "")
+	count := retrieveEventCount(cmd)
+	for _, w := range config.Warnings {
+		fmt.Fprintln(os.Stderr, w)
+	}
+	client, err := config.Client()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var (
+		out     io.Writer = os.Stdout
+		outFile *fileio.RotatingFile
+	)
+	if config.Event.OutFile != "" {
+		outFile, err = fileio.NewRotatingFile(config.Event.OutFile, config.Event.RotateBytes)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		out = io.MultiWriter(os.Stdout, outFile)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// written is kept across resubscriptions so count holds for the whole run
+	var written int
+	for {
+		var eventsChan <-chan *api.Events
+		eventsChan, err = client.EventStream().Stream(ctx, config.Event.Topics, 0, &api.QueryOptions{Namespace: config.Event.Namespace})
+		if err == nil {
+			err = writeEvents(eventsChan, config.Event.JQQuery, out, count, &written)
+			if ctx.Err() != nil {
+				// interrupted, not a failure
+				err = nil
+			}
+		}
+		if !nomad.IsAuthError(err) || config.TokenFile == "" {
+			break
+		}
+
+		// the token may have been rotated, so resubscribe if the token file has a new one
+		token, readErr := nomad.ReadTokenFile(config.TokenFile)
+		if readErr != nil {
+			err = fmt.Errorf("%w, and re-reading token file failed: %v", err, readErr)
+			break
+		}
+		if token == config.Token {
+			err = fmt.Errorf("%w, and the token in %s is unchanged", err, config.TokenFile)
+			break
+		}
+		fmt.Fprintln(os.Stderr, "Token refreshed, resubscribing to events")
+		config.Token = token
+		client.SetSecretID(token)
+	}
+
+	// flush on shutdown
+	if outFile != nil {
+		if closeErr := outFile.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// writeEvents writes jq-filtered events until the stream ends or, if count is positive, count lines have been written
+func writeEvents(eventsChan <-chan *api.Events, code *gojq.Code, out io.Writer, count int, written *int) error {
+	for events := range eventsChan {
+		if events.Err != nil {
+			return events.Err
+		}
+		if events.IsHeartbeat() {
+			continue
+		}
+
+		lines, err := nomad.EventsAsJQLines(events, code)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if _, err = fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+			*written += 1
+			if count > 0 && *written >= count {
+				return nil
+			}
+		}
+	}
+	return nil
+}
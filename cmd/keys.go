@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"github.com/spf13/cobra"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	keysDescription = `Prints the key bindings of each page, read from the same bindings the app uses, in plain text or json.
+Bindings that depend on state, like filtering or saving, are listed as shown when a page first loads.`
+
+	keysCmd = &cobra.Command{
+		Use:   "keys",
+		Short: "Print the key bindings of each page",
+		Long:  keysDescription,
+		Run:   keysEntrypoint,
+	}
+)
+
+type pageKeyBinding struct {
+	Keys []string `json:"keys"`
+	Help string   `json:"help"`
+}
+
+type pageKeyBindings struct {
+	Page     string           `json:"page"`
+	Bindings []pageKeyBinding `json:"bindings"`
+}
+
+func keysEntrypoint(cmd *cobra.Command, args []string) {
+	format := strings.ToLower(retrieveWithDefault(cmd, keysFormatArg, "plain"))
+	if format != "plain" && format != "json" {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("keys format %s must be plain or json", format))
+		os.Exit(1)
+	}
+
+	allPageKeyBindings := getAllPageKeyBindings()
+	if format == "json" {
+		j, err := json.MarshalIndent(allPageKeyBindings, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(j))
+		return
+	}
+
+	for i, p := range allPageKeyBindings {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(p.Page)
+		for _, b := range p.Bindings {
+			fmt.Printf("  %-16s %s\n", strings.Join(b.Keys, ", "), b.Help)
+		}
+	}
+}
+
+// getAllPageKeyBindings lists the bindings of every page in order, showing pages sharing a name, e.g. events pages,
+// once. Optional bindings like ACL policies are included.
+func getAllPageKeyBindings() []pageKeyBindings {
+	var pages []nomad.Page
+	for p := range nomad.GetAllPageConfigs(0, 0, false, nil, 0) {
+		pages = append(pages, p)
+	}
+	sort.Slice(pages, func(x, y int) bool { return pages[x] < pages[y] })
+
+	var all []pageKeyBindings
+	seenPages := make(map[string]bool)
+	for _, p := range pages {
+		if seenPages[p.String()] {
+			continue
+		}
+		seenPages[p.String()] = true
+
+		var bindings []pageKeyBinding
+		seenKeys := make(map[string]bool)
+		rows := nomad.GetPageKeyBindings(p, nomad.KeyHelpState{
+			ACLReadable:            true,
+			QuotasAvailable:        true,
+			MultipleEventJQQueries: true,
+			CapturingResponses:     true,
+		})
+		for _, row := range rows {
+			for _, b := range row {
+				if seenKeys[strings.Join(b.Keys(), ",")] {
+					continue
+				}
+				seenKeys[strings.Join(b.Keys(), ",")] = true
+				bindings = append(bindings, toPageKeyBinding(b))
+			}
+		}
+		all = append(all, pageKeyBindings{Page: p.String(), Bindings: bindings})
+	}
+	return all
+}
+
+func toPageKeyBinding(b key.Binding) pageKeyBinding {
+	return pageKeyBinding{Keys: b.Keys(), Help: b.Help().Desc}
+}
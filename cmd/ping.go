@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/app"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	pingDescription = `Checks that wander can reach Nomad and use its token with the resolved config, without starting the TUI.
+Prints the server version and leader, or the kind of failure with a hint and exits non-zero, e.g. for smoke tests in CI.`
+
+	pingCmd = &cobra.Command{
+		Use:   "ping",
+		Short: "Check the connection to Nomad",
+		Long:  pingDescription,
+		Args:  cobra.NoArgs,
+		Run:   pingEntrypoint,
+	}
+)
+
+var (
+	// errAddressScheme is returned for addresses wander can't connect to
+	errAddressScheme = errors.New("address must start with http:// or https://")
+	// errNoResponse is returned when Nomad doesn't respond within constants.PreflightTimeout
+	errNoResponse = errors.New("no response")
+)
+
+type pingResult struct {
+	leader, version string
+	latency         time.Duration
+}
+
+func pingEntrypoint(cmd *cobra.Command, args []string) {
+	config := getConfig(cmd, "")
+	result, err := ping(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FAIL %s (%s): %s\n", config.URL, connectionErrorCategory(err), explainPreflightError(err))
+		os.Exit(1)
+	}
+	fmt.Printf("OK %s in %s\n", config.URL, result.latency.Round(time.Millisecond))
+	fmt.Printf("  leader   %s\n", result.leader)
+	fmt.Printf("  version  %s\n", result.version)
+}
+
+// ping gets the leader, which requires no token, then lists a job to check the token. The version is only read if
+// the token has agent:read.
+func ping(config app.Config) (pingResult, error) {
+	if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+		return pingResult{}, errAddressScheme
+	}
+
+	client, err := config.Client()
+	if err != nil {
+		return pingResult{}, err
+	}
+
+	type pingResponse struct {
+		result pingResult
+		err    error
+	}
+	responses := make(chan pingResponse, 1)
+	start := time.Now()
+	go func() {
+		leader, err := client.Status().Leader()
+		if err != nil {
+			responses <- pingResponse{err: err}
+			return
+		}
+		if _, _, err = client.Jobs().List(&api.QueryOptions{PerPage: 1}); err != nil {
+			responses <- pingResponse{err: err}
+			return
+		}
+		version := "unknown, the token can't read the agent"
+		if self, err := client.Agent().Self(); err == nil {
+			version = self.Member.Tags["build"]
+		}
+		responses <- pingResponse{result: pingResult{leader: leader, version: version, latency: time.Since(start)}}
+	}()
+
+	select {
+	case r := <-responses:
+		return r.result, r.err
+	case <-time.After(constants.PreflightTimeout):
+		return pingResult{}, fmt.Errorf("%w after %s", errNoResponse, constants.PreflightTimeout)
+	}
+}
+
+// connectionErrorCategory is the kind of failure to connect, in the order explainPreflightError checks them
+func connectionErrorCategory(err error) string {
+	s := err.Error()
+	switch {
+	case errors.Is(err, errAddressScheme):
+		return "address"
+	case isTimeoutError(err):
+		return "timeout"
+	case strings.Contains(s, "server gave HTTP response to HTTPS client"),
+		strings.Contains(s, "HTTP request to an HTTPS server"),
+		strings.Contains(s, "x509"),
+		strings.Contains(s, "tls:"):
+		return "tls"
+	case nomad.IsAuthError(err):
+		return "auth"
+	case strings.Contains(s, "no such host"):
+		return "dns"
+	case strings.Contains(s, "connection refused"):
+		return "connection refused"
+	}
+	return "unknown"
+}
+
+func isTimeoutError(err error) bool {
+	s := err.Error()
+	return errors.Is(err, errNoResponse) || strings.Contains(s, "i/o timeout") || strings.Contains(s, "Client.Timeout exceeded") || strings.Contains(s, "deadline exceeded")
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/app"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"time"
+)
+
+// runPreflight exits with an actionable error if the configured Nomad address can't be used, rather than failing
+// inside the app
+func runPreflight(cmd *cobra.Command) {
+	if trueIfTrue(retrieveWithDefault(cmd, skipPreflightArg, "false")) {
+		return
+	}
+	config := getConfig(cmd, "")
+	if err := preflight(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error connecting to %s: %s\n", config.URL, err.Error())
+		fmt.Fprintf(os.Stderr, "To start anyway, use --%s\n", skipPreflightArg.cliLong)
+		os.Exit(1)
+	}
+
+	if trueIfTrue(retrieveWithDefault(cmd, skipScopeCheckArg, "false")) {
+		return
+	}
+	if err := checkScope(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "To start anyway, e.g. if your token can't list namespaces, use --%s\n", skipScopeCheckArg.cliLong)
+		os.Exit(1)
+	}
+}
+
+func preflight(config app.Config) error {
+	if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+		return explainPreflightError(errAddressScheme)
+	}
+
+	client, err := config.Client()
+	if err != nil {
+		return err
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		if _, err := client.Status().Leader(); err != nil {
+			result <- err
+			return
+		}
+		// the leader check doesn't require a token, so list a job to check it
+		_, _, err := client.Jobs().List(&api.QueryOptions{PerPage: 1})
+		result <- err
+	}()
+
+	select {
+	case err = <-result:
+		return explainPreflightError(err)
+	case <-time.After(constants.PreflightTimeout):
+		return explainPreflightError(fmt.Errorf("%w after %s", errNoResponse, constants.PreflightTimeout))
+	}
+}
+
+// checkScope errors if the configured region or namespace don't exist, listing the ones that do, as Nomad otherwise
+// returns empty lists for them
+func checkScope(config app.Config) error {
+	client, err := config.Client()
+	if err != nil {
+		return err
+	}
+
+	if config.Region != "" {
+		regions, err := client.Regions().List()
+		if err != nil {
+			return fmt.Errorf("could not list regions to check region %s exists (%s)", config.Region, err.Error())
+		}
+		if !inList(config.Region, regions) {
+			return fmt.Errorf("region %s does not exist, valid regions are %s, %s", config.Region, strings.Join(regions, ", "), argHint(regionArg))
+		}
+	}
+
+	if config.Namespace != "" && config.Namespace != "*" {
+		namespaces, _, err := client.Namespaces().List(nil)
+		if err != nil {
+			return fmt.Errorf("could not list namespaces to check namespace %s exists (%s)", config.Namespace, err.Error())
+		}
+		var names []string
+		for _, ns := range namespaces {
+			names = append(names, ns.Name)
+		}
+		if !inList(config.Namespace, names) {
+			return fmt.Errorf("namespace %s does not exist, valid namespaces are %s, %s", config.Namespace, strings.Join(names, ", "), argHint(namespaceArg))
+		}
+	}
+	return nil
+}
+
+func inList(s string, list []string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func explainPreflightError(err error) error {
+	if err == nil {
+		return nil
+	}
+	s := err.Error()
+	switch {
+	case errors.Is(err, errAddressScheme) || isTimeoutError(err):
+		return fmt.Errorf("%s, %s", s, argHint(addrArg))
+	case strings.Contains(s, "server gave HTTP response to HTTPS client"):
+		return fmt.Errorf("the server doesn't use TLS, use http:// in the address, %s", argHint(addrArg))
+	case strings.Contains(s, "HTTP request to an HTTPS server"):
+		return fmt.Errorf("the server uses TLS, use https:// in the address, %s", argHint(addrArg))
+	case strings.Contains(s, "x509") || strings.Contains(s, "tls:"):
+		return fmt.Errorf("TLS failed (%s), %s, or to not verify certificates, %s", s, argHint(cacertArg), argHint(skipVerifyArg))
+	case nomad.IsAuthError(err):
+		return fmt.Errorf("token rejected (%s), %s", s, argHint(tokenArg))
+	case strings.Contains(s, "no such host"):
+		return fmt.Errorf("could not resolve the server's host name (%s), %s", s, argHint(addrArg))
+	case strings.Contains(s, "connection refused"):
+		return fmt.Errorf("could not reach the server (%s), %s", s, argHint(addrArg))
+	}
+	return err
+}
+
+func argHint(a arg) string {
+	return fmt.Sprintf("check --%s or %s", a.cliLong, strings.ToUpper(a.cfgFileEnvVar))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/tui/components/app"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"os"
@@ -53,6 +54,11 @@ var (
 		cfgFileEnvVar: "nomad_token",
 		description:   `Nomad token. Default ""`,
 	}
+	tokenFileArg = arg{
+		cliLong:       "token-file",
+		cfgFileEnvVar: "wander_token_file",
+		description:   `File containing the Nomad token, re-read if the token is rejected, e.g. after rotation. Default ""`,
+	}
 	regionArg = arg{
 		cliShort:      "r",
 		cliLong:       "region",
@@ -65,6 +71,11 @@ var (
 		cfgFileEnvVar: "nomad_namespace",
 		description:   `Nomad namespace. Default "*"`,
 	}
+	namespaceFromTokenArg = arg{
+		cliLong:       "namespace-from-token",
+		cfgFileEnvVar: "wander_namespace_from_token",
+		description:   `If "true" and no namespace is set, use the namespace the token's policies grant if they grant only one. Default "false"`,
+	}
 	httpAuthArg = arg{
 		cliLong:       "http-auth",
 		cfgFileEnvVar: "nomad_http_auth",
@@ -106,18 +117,179 @@ var (
 		cfgFileEnvVar: "wander_update_seconds",
 		description:   `Seconds between updates for job & allocation pages. Disable with "-1". Default "2"`,
 	}
+	updateJitterArg = arg{
+		cliLong:       "update-jitter",
+		cfgFileEnvVar: "wander_update_jitter",
+		description:   `Percent by which the time between updates randomly varies, so many instances don't update in sync. Disable with "0". Default "5"`,
+	}
+	apiTimeoutArg = arg{
+		cliLong:       "api-timeout",
+		cfgFileEnvVar: "wander_api_timeout",
+		description:   `Seconds to wait for a response from the Nomad API before timing out. Disable with "-1". Default "30"`,
+	}
+	exitOnDisconnectArg = arg{
+		cliLong:       "exit-on-disconnect",
+		cfgFileEnvVar: "wander_exit_on_disconnect",
+		description:   `Exit with an error after Nomad is unreachable for this long, e.g. "5m", so a supervisor can restart wander. Disable with "0". Default "0"`,
+	}
+	dimAfterArg = arg{
+		cliLong:       "dim-after",
+		cfgFileEnvVar: "wander_dim_after",
+		description:   `Dim the view after this long without input, e.g. "10m", for always-on dashboards. Any key restores it. Disable with "0". Default "0"`,
+	}
+	logMaxLineLengthArg = arg{
+		cliLong:       "log-max-line-length",
+		cfgFileEnvVar: "wander_log_max_line_length",
+		description:   `Bytes of a log line shown before it's marked [truncated]. Saving logs keeps full lines. Disable with "0". Default "10000"`,
+	}
+	logCoalesceArg = arg{
+		cliLong:       "log-coalesce",
+		cfgFileEnvVar: "wander_log_coalesce",
+		description:   `How long to batch followed log lines for before rendering them, e.g. "100ms". "0" renders lines as they arrive. Default "50ms"`,
+	}
 	logOffsetArg = arg{
 		cliShort:      "o",
 		cliLong:       "log-offset",
 		cfgFileEnvVar: "wander_log_offset",
 		description:   `Log byte offset from which logs start. Default "1000000"`,
 	}
+	logsFromStartArg = arg{
+		cliLong:       "logs-from-start",
+		cfgFileEnvVar: "wander_logs_from_start",
+		description:   `Show logs from the start instead of the end, e.g. for batch jobs. Default "false"`,
+	}
+	debugLogArg = arg{
+		cliLong:       "debug-log",
+		cfgFileEnvVar: "wander_debug_log",
+		description:   `File to append diagnostics to, e.g. api requests and errors, with the token redacted. Default ""`,
+	}
+	debugLevelArg = arg{
+		cliLong:       "debug-level",
+		cfgFileEnvVar: "wander_debug_level",
+		description:   `Verbosity of the debug log: error, info for api requests, or debug for every ui message. Default "info"`,
+	}
+	captureResponsesArg = arg{
+		cliLong:       "capture-responses",
+		cfgFileEnvVar: "wander_capture_responses",
+		description:   `If "true", keep the last api responses to view raw with Z, e.g. to debug api version mismatches. Default "false"`,
+	}
+	hideDeadJobsArg = arg{
+		cliLong:       "hide-dead-jobs",
+		cfgFileEnvVar: "wander_hide_dead_jobs",
+		description:   `If "true", start with dead jobs hidden from the jobs list. Toggle with i. Default "false"`,
+	}
+	jobsPerPageArg = arg{
+		cliLong:       "jobs-per-page",
+		cfgFileEnvVar: "wander_jobs_per_page",
+		description:   `Jobs to fetch at a time, fetching more as you scroll or every page once you filter, for clusters with thousands. Ignored if jobs are sorted. "0" for all at once. Default "0"`,
+	}
+	jobTypeArg = arg{
+		cliLong:       "job-type",
+		cfgFileEnvVar: "wander_job_type",
+		description:   `Job type to start the jobs list narrowed to: all, service, batch, system or sysbatch. Cycle with t. Default "all"`,
+	}
+	showDatacentersArg = arg{
+		cliLong:       "show-datacenters",
+		cfgFileEnvVar: "wander_show_datacenters",
+		description:   `If "true", show the datacenters of jobs and of allocations' nodes, for multi-datacenter clusters. Default "false"`,
+	}
+	iconsArg = arg{
+		cliLong:       "icons",
+		cfgFileEnvVar: "wander_icons",
+		description:   `Icons shown next to job and task statuses, "nerdfont" (requires a nerd font), "ascii" or "none". Default "none"`,
+	}
+	noAltScreenArg = arg{
+		cliLong:       "no-alt-screen",
+		cfgFileEnvVar: "wander_no_alt_screen",
+		description:   `If "true", run inline instead of in the terminal's alternate screen, keeping its scrollback and selection. Default "false"`,
+	}
+	maxRowsArg = arg{
+		cliLong:       "max-rows",
+		cfgFileEnvVar: "wander_max_rows",
+		description:   `Maximum rows shown in tables like jobs and allocations, or "auto" to fill the terminal. Default "auto"`,
+	}
+	maxWidthArg = arg{
+		cliLong:       "max-width",
+		cfgFileEnvVar: "wander_max_width",
+		description:   `Maximum width of the app, centered in wider terminals. Default 0, i.e. no maximum`,
+	}
+	maxHeightArg = arg{
+		cliLong:       "max-height",
+		cfgFileEnvVar: "wander_max_height",
+		description:   `Maximum height of the app, centered in taller terminals. Default 0, i.e. no maximum`,
+	}
 	copySavePathArg = arg{
 		cliShort:      "s",
 		cliLong:       "copy-save-path",
 		cfgFileEnvVar: "wander_copy_save_path",
 		description:   `If "true", copy the full path to file after save. Default "false"`,
 	}
+	profileNameArg = arg{
+		cliLong:       "profile-name",
+		cfgFileEnvVar: "wander_profile_name",
+		description:   `Name of an auth profile in wander_profiles to use. Default none, i.e. ""`,
+	}
+	quietArg = arg{
+		cliLong:       "quiet",
+		cfgFileEnvVar: "wander_quiet",
+		description:   `If "true", suppress informational output like deprecation warnings. Errors are still printed. Default "false"`,
+	}
+	strictConfigArg = arg{
+		cliLong:       "strict-config",
+		cfgFileEnvVar: "wander_strict_config",
+		description:   `If "true", exit on unknown keys or wrong value types in the config file instead of warning. Default "false"`,
+	}
+	skipPreflightArg = arg{
+		cliLong:       "skip-preflight",
+		cfgFileEnvVar: "wander_skip_preflight",
+		description:   `If "true", start without first checking the Nomad address, TLS and token work. Default "false"`,
+	}
+	skipScopeCheckArg = arg{
+		cliLong:       "skip-scope-check",
+		cfgFileEnvVar: "wander_skip_scope_check",
+		description:   `If "true", start without first checking the namespace and region exist, e.g. for tokens that can't list namespaces. Default "false"`,
+	}
+	exportFormatArg = arg{
+		cliLong:       "export-format",
+		cfgFileEnvVar: "wander_export_format",
+		description:   `Format of exported allocations, "json" or "yaml". Default "json"`,
+	}
+	clipboardArg = arg{
+		cliLong:       "clipboard",
+		cfgFileEnvVar: "wander_clipboard",
+		description:   `Comma-separated clipboard strategies to try in order, "system" and "osc52". Default "system,osc52"`,
+	}
+	batchArg = arg{
+		cliLong:       "batch",
+		cfgFileEnvVar: "wander_batch",
+		description:   `If "true", print the jobs page once and exit instead of starting the interactive app. Default "false"`,
+	}
+	filterArg = arg{
+		cliLong:       "filter",
+		cfgFileEnvVar: "wander_filter",
+		description:   `Filter applied to the first page on startup. Default none, i.e. ""`,
+	}
+	openArg = arg{
+		cliLong:       "open",
+		cfgFileEnvVar: "wander_open",
+		description:   `Link copied with w in wander to open instead of the jobs page, e.g. "wander://logs?alloc=<id>&task=web". Default none, i.e. ""`,
+	}
+	stateFileArg = arg{
+		cliLong:       "state-file",
+		cfgFileEnvVar: "wander_state_file",
+		description:   `Path to the file wander persists state like filter history to. Default "$HOME/.wander_state.json"`,
+	}
+	readOnlyArg = arg{
+		cliLong:       "read-only",
+		cfgFileEnvVar: "wander_read_only",
+		description:   `If "true", disable actions that modify the cluster, e.g. stopping allocations. Default "false"`,
+	}
+	sortArg = arg{
+		cfgFileEnvVar: "wander_sort",
+	}
+	confirmTypedArg = arg{
+		cfgFileEnvVar: "wander_confirm_typed",
+	}
 	eventTopicsArg = arg{
 		cliLong:       "event-topics",
 		cfgFileEnvVar: "wander_event_topics",
@@ -133,9 +305,72 @@ var (
 		cfgFileEnvVar: "wander_event_jq_query",
 		description:   `jq query for events. "." for entire JSON. Default shown at https://github.com/robinovitch61/wander`,
 	}
+	eventOutFileArg = arg{
+		cliLong:       "out-file",
+		cfgFileEnvVar: "wander_event_out_file",
+		description:   `File to which followed events are appended as JSON lines. Default none, i.e. ""`,
+	}
+	eventRotateArg = arg{
+		cliLong:       "rotate",
+		cfgFileEnvVar: "wander_event_rotate",
+		description:   `Size at which the events out file is rotated, e.g. "100MB". Disable with "0". Default "100MB"`,
+	}
+	eventCountArg = arg{
+		cliLong:       "count",
+		cfgFileEnvVar: "wander_event_count",
+		description:   `For wander events, exit after this many jq-filtered events. "0" for no limit. Default "0"`,
+	}
+	nomadDataDirArg = arg{
+		cliLong:       "nomad-data-dir",
+		cfgFileEnvVar: "wander_nomad_data_dir",
+		description:   `Nomad data_dir on client nodes, used to copy task log paths. Default "/opt/nomad/data"`,
+	}
+	nomadUIURLArg = arg{
+		cliLong:       "nomad-ui-url",
+		cfgFileEnvVar: "wander_nomad_ui_url",
+		description:   `Base URL of the Nomad web UI for ui-url. Default is the address with "/ui" appended`,
+	}
+	keysFormatArg = arg{
+		cliLong:       "format",
+		cfgFileEnvVar: "wander_keys_format",
+		description:   `Format of the key bindings printed by keys, "plain" or "json". Default "plain"`,
+	}
+	logRedactionsArg = arg{
+		cfgFileEnvVar: "wander_log_redactions",
+	}
+	defaultLogTaskArg = arg{
+		cfgFileEnvVar: "wander_default_log_task",
+	}
+	logoArg = arg{
+		cfgFileEnvVar: "wander_logo",
+	}
 	logoColorArg = arg{
 		cfgFileEnvVar: "wander_logo_color",
 	}
+	eventJQQueriesArg = arg{
+		cfgFileEnvVar: "wander_event_jq_queries",
+	}
+	footerHintsArg = arg{
+		cfgFileEnvVar: "wander_footer_hints",
+	}
+	customActionsArg = arg{
+		cfgFileEnvVar: "wander_custom_actions",
+	}
+	watchesArg = arg{
+		cfgFileEnvVar: "wander_watches",
+	}
+	namespaceColorsArg = arg{
+		cfgFileEnvVar: "wander_namespace_colors",
+	}
+	defaultNamespaceColorArg = arg{
+		cfgFileEnvVar: "wander_default_namespace_color",
+	}
+	profilesArg = arg{
+		cfgFileEnvVar: "wander_profiles",
+	}
+	highlightRulesArg = arg{
+		cfgFileEnvVar: "wander_highlight_rules",
+	}
 
 	description = `wander is a terminal application for Nomad by HashiCorp. It is used to
 view jobs, allocations, tasks, logs, and more, all from the terminal
@@ -165,8 +400,10 @@ func init() {
 	for _, c := range []arg{
 		addrArg,
 		tokenArg,
+		tokenFileArg,
 		regionArg,
 		namespaceArg,
+		namespaceFromTokenArg,
 		httpAuthArg,
 		cacertArg,
 		capathArg,
@@ -175,11 +412,45 @@ func init() {
 		tlsServerNameArg,
 		skipVerifyArg,
 		updateSecondsArg,
+		updateJitterArg,
+		apiTimeoutArg,
+		exitOnDisconnectArg,
+		dimAfterArg,
 		logOffsetArg,
+		logMaxLineLengthArg,
+		logCoalesceArg,
+		logsFromStartArg,
+		hideDeadJobsArg,
+		jobTypeArg,
+		jobsPerPageArg,
+		debugLogArg,
+		debugLevelArg,
+		captureResponsesArg,
+		maxRowsArg,
+		showDatacentersArg,
+		iconsArg,
+		noAltScreenArg,
+		maxWidthArg,
+		maxHeightArg,
 		copySavePathArg,
+		exportFormatArg,
+		clipboardArg,
+		profileNameArg,
+		quietArg,
+		strictConfigArg,
+		skipPreflightArg,
+		skipScopeCheckArg,
+		batchArg,
+		filterArg,
+		openArg,
+		stateFileArg,
+		readOnlyArg,
 		eventTopicsArg,
 		eventNamespaceArg,
 		eventJQQueryArg,
+		eventOutFileArg,
+		eventRotateArg,
+		nomadDataDirArg,
 	} {
 		rootCmd.PersistentFlags().StringP(c.cliLong, c.cliShort, "", c.description)
 		viper.BindPFlag(c.cliLong, rootCmd.PersistentFlags().Lookup(c.cfgFileEnvVar))
@@ -199,7 +470,24 @@ func init() {
 		viper.BindPFlag(c.cliLong, serveCmd.PersistentFlags().Lookup(c.cfgFileEnvVar))
 	}
 
+	// ui-url
+	uiURLCmd.PersistentFlags().StringP(nomadUIURLArg.cliLong, nomadUIURLArg.cliShort, "", nomadUIURLArg.description)
+	viper.BindPFlag(nomadUIURLArg.cliLong, uiURLCmd.PersistentFlags().Lookup(nomadUIURLArg.cfgFileEnvVar))
+
+	// events
+	eventsCmd.PersistentFlags().StringP(eventCountArg.cliLong, eventCountArg.cliShort, "", eventCountArg.description)
+	viper.BindPFlag(eventCountArg.cliLong, eventsCmd.PersistentFlags().Lookup(eventCountArg.cfgFileEnvVar))
+
+	// keys
+	keysCmd.PersistentFlags().StringP(keysFormatArg.cliLong, keysFormatArg.cliShort, "", keysFormatArg.description)
+	viper.BindPFlag(keysFormatArg.cliLong, keysCmd.PersistentFlags().Lookup(keysFormatArg.cfgFileEnvVar))
+
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(uiURLCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(pingCmd)
 }
 
 func initConfig() {
@@ -207,13 +495,13 @@ func initConfig() {
 		// Use config file from the flag.
 		_, err := os.Stat(cfgFile)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
 		extension := filepath.Ext(cfgFile)
 		if extension != ".yaml" && extension != ".yml" {
-			fmt.Println("error: config file must be .yaml or .yml")
+			fmt.Fprintln(os.Stderr, "error: config file must be .yaml or .yml")
 			os.Exit(1)
 		}
 
@@ -230,17 +518,43 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		if !retrieveQuiet(rootCmd) {
+			fmt.Println("Using config file:", viper.ConfigFileUsed())
+		}
+
+		problems, err := validateConfigFile(viper.ConfigFileUsed())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config file: %s\n", err.Error())
+			os.Exit(1)
+		}
+		if len(problems) > 0 && trueIfTrue(retrieveWithDefault(rootCmd, strictConfigArg, "false")) {
+			for _, p := range problems {
+				fmt.Fprintln(os.Stderr, "error: "+p)
+			}
+			os.Exit(1)
+		}
+		configFileProblems = problems
 	}
 }
 
 func mainEntrypoint(cmd *cobra.Command, args []string) {
-	initialModel, options := setup(cmd, "")
+	if retrieveBatch(cmd) {
+		batchEntrypoint(cmd)
+		return
+	}
+
+	runPreflight(cmd)
+	initialModel, options := setup(cmd, "", nil, nil)
 	program := tea.NewProgram(initialModel, options...)
 
 	dev.Debug("~STARTING UP~")
-	if err := program.Start(); err != nil {
-		fmt.Printf("Error on wander startup: %v", err)
+	finalModel, err := program.StartReturningModel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error on wander startup: %v", err)
+		os.Exit(1)
+	}
+	if m, ok := finalModel.(app.Model); ok && m.ExitErr() != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", m.ExitErr())
 		os.Exit(1)
 	}
 }
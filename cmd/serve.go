@@ -7,6 +7,8 @@ import (
 	"github.com/charmbracelet/wish"
 	bm "github.com/charmbracelet/wish/bubbletea"
 	"github.com/gliderlabs/ssh"
+	"github.com/muesli/termenv"
+	"github.com/robinovitch61/wander/internal/tui/components/app"
 	"github.com/spf13/cobra"
 	"log"
 	"os"
@@ -58,11 +60,12 @@ func serveEntrypoint(cmd *cobra.Command, args []string) {
 	portStr := retrieveWithDefault(cmd, portArg, "21324")
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
-		fmt.Println(fmt.Errorf("could not convert %s to integer", portStr))
+		fmt.Fprintln(os.Stderr, fmt.Errorf("could not convert %s to integer", portStr))
 		os.Exit(1)
 	}
 	hostKeyPath := retrieveWithDefault(cmd, hostKeyPathArg, "")
 	hostKeyPEM := retrieveWithDefault(cmd, hostKeyPEMArg, "")
+	runPreflight(cmd)
 
 	options := []ssh.Option{wish.WithAddress(fmt.Sprintf("%s:%d", host, port))}
 	if hostKeyPath != "" {
@@ -72,7 +75,7 @@ func serveEntrypoint(cmd *cobra.Command, args []string) {
 		options = append(options, wish.WithHostKeyPEM([]byte(hostKeyPEM)))
 	}
 	middleware := wish.WithMiddleware(
-		bm.Middleware(generateTeaHandler(cmd)),
+		bm.MiddlewareWithProgramHandler(generateProgramHandler(cmd), termenv.ANSI256),
 		customLoggingMiddleware(),
 	)
 	options = append(options, middleware)
@@ -100,13 +103,17 @@ func serveEntrypoint(cmd *cobra.Command, args []string) {
 	}
 }
 
-func generateTeaHandler(cmd *cobra.Command) func(ssh.Session) (tea.Model, []tea.ProgramOption) {
-	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func generateProgramHandler(cmd *cobra.Command) bm.ProgramHandler {
+	return func(s ssh.Session) *tea.Program {
 		// optionally override token - MUST run with `-t` flag to force pty, e.g. ssh -p 20000 localhost -t <token>
 		var overrideToken string
 		if sshCommands := s.Command(); len(sshCommands) == 1 {
 			overrideToken = strings.TrimSpace(sshCommands[0])
 		}
-		return setup(cmd, overrideToken)
+		// the program renders through the same output that notifications and copies are written to, so they don't
+		// interleave with its frames
+		output := app.NewOutput(s)
+		initialModel, options := setup(cmd, overrideToken, output, s.Environ())
+		return tea.NewProgram(initialModel, append(options, tea.WithInput(s), tea.WithOutput(output))...)
 	}
 }
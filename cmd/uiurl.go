@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var (
+	uiURLDescription = `Prints the Nomad web UI URL of a job or allocation without starting the TUI.`
+
+	uiURLCmd = &cobra.Command{
+		Use:       "ui-url (job|alloc) <id>",
+		Short:     "Print the Nomad web UI URL of a resource",
+		Long:      uiURLDescription,
+		Args:      cobra.ExactValidArgs(2),
+		ValidArgs: []string{"job", "alloc"},
+		Run:       uiURLEntrypoint,
+	}
+)
+
+func uiURLEntrypoint(cmd *cobra.Command, args []string) {
+	config := getConfig(cmd, "")
+	base := retrieveWithDefault(cmd, nomadUIURLArg, strings.TrimRight(config.URL, "/")+"/ui")
+
+	uiURL, err := getUIURL(base, args[0], args[1], config.Namespace, config.Region)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(uiURL)
+}
+
+func getUIURL(base, resourceType, id, namespace, region string) (string, error) {
+	u, err := url.Parse(strings.TrimRight(base, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid nomad ui url %s: %w", base, err)
+	}
+
+	query := u.Query()
+	switch resourceType {
+	case "job":
+		appendPathSegment(u, "jobs", id)
+		// allocations are unique across namespaces, so only jobs need it
+		if namespace != "" {
+			query.Set("namespace", namespace)
+		}
+	case "alloc":
+		appendPathSegment(u, "allocations", id)
+	default:
+		return "", fmt.Errorf("unknown resource type %s, expected job or alloc", resourceType)
+	}
+	if region != "" {
+		query.Set("region", region)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// appendPathSegment appends name and id to u's path, keeping any slashes in id escaped as part of the one segment
+func appendPathSegment(u *url.URL, name, id string) {
+	u.RawPath = u.EscapedPath() + "/" + name + "/" + url.PathEscape(id)
+	u.Path += "/" + name + "/" + id
+}
@@ -8,15 +8,22 @@ import (
 	"github.com/gliderlabs/ssh"
 	"github.com/hashicorp/nomad/api"
 	"github.com/itchyny/gojq"
+	"github.com/robinovitch61/wander/internal/dev"
 	"github.com/robinovitch61/wander/internal/tui/components/app"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
 	"github.com/robinovitch61/wander/internal/tui/constants"
 	"github.com/robinovitch61/wander/internal/tui/nomad"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -62,8 +69,7 @@ func retrieveWithFallback(cmd *cobra.Command, currArg, oldArg arg) (string, erro
 		if val == "" {
 			return "", err
 		}
-		fmt.Printf("\nwarning: use of %s env variable or %s in config file will be removed in a future release\n", strings.ToUpper(oldArg.cfgFileEnvVar), oldArg.cfgFileEnvVar)
-		fmt.Printf("use %s env variable or %s in config file instead\n", strings.ToUpper(currArg.cfgFileEnvVar), currArg.cfgFileEnvVar)
+		recordDeprecation(deprecation{Old: oldArg, Replacement: currArg})
 	}
 	return val, nil
 }
@@ -102,12 +108,30 @@ func retrieveToken(cmd *cobra.Command) string {
 	}
 	err = validateToken(val)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 	return val
 }
 
+// retrieveTokenFile returns the token file and, if no token is otherwise configured, the token in it
+func retrieveTokenFile(cmd *cobra.Command, token string) (string, string) {
+	tokenFile := retrieveWithDefault(cmd, tokenFileArg, "")
+	if tokenFile == "" || token != "" {
+		return tokenFile, token
+	}
+	token, err := nomad.ReadTokenFile(tokenFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading token file: %s\n", err.Error())
+		os.Exit(1)
+	}
+	if err = validateToken(token); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	return tokenFile, token
+}
+
 func retrieveRegion(cmd *cobra.Command) string {
 	return retrieveWithDefault(cmd, regionArg, "")
 }
@@ -116,6 +140,11 @@ func retrieveNamespace(cmd *cobra.Command) string {
 	return retrieveWithDefault(cmd, namespaceArg, "*")
 }
 
+func retrieveNamespaceFromToken(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, namespaceFromTokenArg, "false")
+	return trueIfTrue(v)
+}
+
 func retrieveHTTPAuth(cmd *cobra.Command) string {
 	return retrieveWithDefault(cmd, httpAuthArg, "")
 }
@@ -150,7 +179,57 @@ func retrieveCopySavePath(cmd *cobra.Command) bool {
 	return trueIfTrue(v)
 }
 
+func retrieveLogsFromStart(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, logsFromStartArg, "false")
+	return trueIfTrue(v)
+}
+
+func retrieveHideDeadJobs(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, hideDeadJobsArg, "false")
+	return trueIfTrue(v)
+}
+
+func retrieveJobType(cmd *cobra.Command) string {
+	jobType := strings.ToLower(retrieveWithDefault(cmd, jobTypeArg, "all"))
+	if jobType == "all" {
+		return ""
+	}
+	var valid bool
+	for _, t := range nomad.JobTypes {
+		valid = valid || (t != "" && t == jobType)
+	}
+	if !valid {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("job type %s must be all, service, batch, system or sysbatch", jobType))
+		os.Exit(1)
+	}
+	return jobType
+}
+
+func retrieveCaptureResponses(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, captureResponsesArg, "false")
+	return trueIfTrue(v)
+}
+
+func retrieveShowDatacenters(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, showDatacentersArg, "false")
+	return trueIfTrue(v)
+}
+
+func retrieveNoAltScreen(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, noAltScreenArg, "false")
+	return trueIfTrue(v)
+}
+
+func retrieveReadOnly(cmd *cobra.Command) bool {
+	v := retrieveWithDefault(cmd, readOnlyArg, "false")
+	return trueIfTrue(v)
+}
+
 func retrieveEventTopics(cmd *cobra.Command) nomad.Topics {
+	return parseEventTopics(retrieveWithDefault(cmd, eventTopicsArg, "Job,Allocation,Deployment,Evaluation"))
+}
+
+func parseEventTopics(topicString string) nomad.Topics {
 	matchTopic := func(t string) (api.Topic, error) {
 		switch t {
 		case "Deployment":
@@ -171,7 +250,6 @@ func retrieveEventTopics(cmd *cobra.Command) nomad.Topics {
 		return "", fmt.Errorf("%s cannot be parsed into topic", t)
 	}
 
-	topicString := retrieveWithDefault(cmd, eventTopicsArg, "Job,Allocation,Deployment,Evaluation")
 	topics := make(nomad.Topics)
 	for _, t := range strings.Split(topicString, ",") {
 		split := strings.Split(strings.TrimSpace(t), ":")
@@ -182,7 +260,7 @@ func retrieveEventTopics(cmd *cobra.Command) nomad.Topics {
 
 		topic, err := matchTopic(strings.TrimSpace(split[0]))
 		if err != nil {
-			fmt.Println(err.Error())
+			fmt.Fprintln(os.Stderr, err.Error())
 			os.Exit(1)
 		}
 
@@ -203,66 +281,482 @@ func retrieveEventNamespace(cmd *cobra.Command) string {
 
 func retrieveEventJQQuery(cmd *cobra.Command) *gojq.Code {
 	query := retrieveWithDefault(cmd, eventJQQueryArg, constants.DefaultEventJQQuery)
+	code, err := compileJQQuery(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in event jq query: %s\n", err.Error())
+		os.Exit(1)
+	}
+	return code
+}
+
+type namedJQQuery struct {
+	Name  string `mapstructure:"name"`
+	Query string `mapstructure:"query"`
+}
+
+// retrieveEventJQQueries compiles the named jq queries that can be cycled through on events pages
+func retrieveEventJQQueries() []app.NamedJQQuery {
+	var queries []namedJQQuery
+	if err := viper.UnmarshalKey(eventJQQueriesArg.cfgFileEnvVar, &queries); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", eventJQQueriesArg.cfgFileEnvVar, err.Error())
+		os.Exit(1)
+	}
+	var compiled []app.NamedJQQuery
+	for _, q := range queries {
+		if q.Name == "" || q.Query == "" {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: each query requires a name and query\n", eventJQQueriesArg.cfgFileEnvVar)
+			os.Exit(1)
+		}
+		code, err := compileJQQuery(q.Query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error in %s query %s: %s\n", eventJQQueriesArg.cfgFileEnvVar, q.Name, err.Error())
+			os.Exit(1)
+		}
+		compiled = append(compiled, app.NamedJQQuery{Name: q.Name, Code: code})
+	}
+	return compiled
+}
+
+func compileJQQuery(query string) (*gojq.Code, error) {
 	parsed, err := gojq.Parse(query)
 	if err != nil {
-		fmt.Printf("Error parsing event jq query: %s\n", err.Error())
+		return nil, err
+	}
+	return gojq.Compile(parsed)
+}
+
+func retrieveEventOutFile(cmd *cobra.Command) string {
+	return retrieveWithDefault(cmd, eventOutFileArg, "")
+}
+
+func retrieveEventRotate(cmd *cobra.Command) int64 {
+	rotateString := retrieveWithDefault(cmd, eventRotateArg, "100MB")
+	rotate, err := parseByteSize(rotateString)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("rotate value %s cannot be converted to a size, e.g. 100MB", rotateString))
+		os.Exit(1)
+	}
+	return rotate
+}
+
+func retrieveEventCount(cmd *cobra.Command) int {
+	countString := retrieveWithDefault(cmd, eventCountArg, "0")
+	count, err := strconv.Atoi(countString)
+	if err != nil || count < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("event count %s must be a non-negative integer", countString))
 		os.Exit(1)
 	}
-	code, err := gojq.Compile(parsed)
+	return count
+}
+
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+	size, err := strconv.ParseFloat(s, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid size %s", s)
+	}
+	return int64(size * float64(multiplier)), nil
+}
+
+func retrieveQuiet(cmd *cobra.Command) bool {
+	return trueIfTrue(retrieveWithDefault(cmd, quietArg, "false"))
+}
+
+func retrieveStateFile(cmd *cobra.Command) string {
+	stateFile := retrieveWithDefault(cmd, stateFileArg, "")
+	if stateFile != "" {
+		return stateFile
+	}
+	home, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("Error compiling event jq query: %s\n", err.Error())
+		// without a home directory, state isn't persisted
+		return ""
+	}
+	return filepath.Join(home, ".wander_state.json")
+}
+
+func retrieveBatch(cmd *cobra.Command) bool {
+	return trueIfTrue(retrieveWithDefault(cmd, batchArg, "false"))
+}
+
+func retrieveLogRedactions() []*regexp.Regexp {
+	patterns := constants.DefaultLogRedactions
+	if viper.IsSet(logRedactionsArg.cfgFileEnvVar) {
+		patterns = viper.GetStringSlice(logRedactionsArg.cfgFileEnvVar)
+	}
+	var redactions []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: invalid regex %q: %s\n", logRedactionsArg.cfgFileEnvVar, p, err.Error())
+			os.Exit(1)
+		}
+		redactions = append(redactions, re)
+	}
+	return redactions
+}
+
+// retrieveDefaultLogTasks compiles the patterns of preferred tasks, each matching a whole task name so plain names work
+func retrieveDefaultLogTasks() []*regexp.Regexp {
+	var preferred []*regexp.Regexp
+	for _, p := range viper.GetStringSlice(defaultLogTaskArg.cfgFileEnvVar) {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: invalid regex %q: %s\n", defaultLogTaskArg.cfgFileEnvVar, p, err.Error())
+			os.Exit(1)
+		}
+		preferred = append(preferred, re)
+	}
+	return preferred
+}
+
+// retrieveSort parses the sort of each page by name, exiting on unknown pages or fields
+func retrieveSort() map[nomad.Page][]page.SortKey {
+	pagesByName := make(map[string]nomad.Page)
+	for p := range nomad.GetAllPageConfigs(0, 0, false, nil, 0) {
+		if p.SortFields() != nil {
+			pagesByName[p.String()] = p
+		}
+	}
+
+	sortByPage := make(map[nomad.Page][]page.SortKey)
+	for name, s := range viper.GetStringMapString(sortArg.cfgFileEnvVar) {
+		p, exists := pagesByName[strings.ToLower(name)]
+		if !exists {
+			var names []string
+			for n := range pagesByName {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(os.Stderr, "Error parsing %s: page %s can't be sorted, pages are %s\n", sortArg.cfgFileEnvVar, name, strings.Join(names, ", "))
+			os.Exit(1)
+		}
+		keys, err := page.ParseSortKeys(s, p.SortFields())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s for page %s: %s\n", sortArg.cfgFileEnvVar, name, err.Error())
+			os.Exit(1)
+		}
+		sortByPage[p] = keys
+	}
+	return sortByPage
+}
+
+func retrieveConfirmTyped() []string {
+	return viper.GetStringSlice(confirmTypedArg.cfgFileEnvVar)
+}
+
+func retrieveFooterHints() []app.FooterHint {
+	var hints []app.FooterHint
+	if err := viper.UnmarshalKey(footerHintsArg.cfgFileEnvVar, &hints); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", footerHintsArg.cfgFileEnvVar, err.Error())
 		os.Exit(1)
 	}
-	return code
+	for _, h := range hints {
+		if h.Key == "" || h.Label == "" {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: each hint requires a key and label\n", footerHintsArg.cfgFileEnvVar)
+			os.Exit(1)
+		}
+	}
+	return hints
+}
+
+func retrieveCustomActions() []app.CustomAction {
+	var actions []app.CustomAction
+	if err := viper.UnmarshalKey(customActionsArg.cfgFileEnvVar, &actions); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", customActionsArg.cfgFileEnvVar, err.Error())
+		os.Exit(1)
+	}
+	for _, a := range actions {
+		if _, _, err := app.ParseCustomAction(a); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", customActionsArg.cfgFileEnvVar, err.Error())
+			os.Exit(1)
+		}
+	}
+	return actions
+}
+
+func retrieveWatches() []app.JobWatch {
+	var watches []app.JobWatch
+	if err := viper.UnmarshalKey(watchesArg.cfgFileEnvVar, &watches); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", watchesArg.cfgFileEnvVar, err.Error())
+		os.Exit(1)
+	}
+	for _, w := range watches {
+		if err := app.ValidateJobWatch(w); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", watchesArg.cfgFileEnvVar, err.Error())
+			os.Exit(1)
+		}
+	}
+	return watches
+}
+
+type highlightRule struct {
+	Match string `mapstructure:"match"`
+	Color string `mapstructure:"color"`
+}
+
+func retrieveHighlightRules() []page.HighlightRule {
+	var rules []highlightRule
+	if err := viper.UnmarshalKey(highlightRulesArg.cfgFileEnvVar, &rules); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", highlightRulesArg.cfgFileEnvVar, err.Error())
+		os.Exit(1)
+	}
+	var highlightRules []page.HighlightRule
+	for _, r := range rules {
+		h, err := page.NewHighlightRule(r.Match, r.Color)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", highlightRulesArg.cfgFileEnvVar, err.Error())
+			os.Exit(1)
+		}
+		highlightRules = append(highlightRules, h)
+	}
+	return highlightRules
+}
+
+// authProfile overlays non-empty values onto the resolved config when selected by name, so each cluster can have its
+// own default namespace, region and event topics
+type authProfile struct {
+	Addr           string `mapstructure:"addr"`
+	Token          string `mapstructure:"token"`
+	Region         string `mapstructure:"region"`
+	Namespace      string `mapstructure:"namespace"`
+	HTTPAuth       string `mapstructure:"http_auth"`
+	EventTopics    string `mapstructure:"event_topics"`
+	EventNamespace string `mapstructure:"event_namespace"`
+}
+
+func retrieveProfile(cmd *cobra.Command) (string, authProfile) {
+	name := retrieveWithDefault(cmd, profileNameArg, "")
+	if name == "" {
+		return "", authProfile{}
+	}
+
+	var profiles map[string]authProfile
+	if err := viper.UnmarshalKey(profilesArg.cfgFileEnvVar, &profiles); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %s\n", profilesArg.cfgFileEnvVar, err.Error())
+		os.Exit(1)
+	}
+	profile, exists := profiles[name]
+	if !exists {
+		var names []string
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "error: profile %s not found in %s, available profiles: %s\n", name, profilesArg.cfgFileEnvVar, strings.Join(names, ", "))
+		os.Exit(1)
+	}
+	if err := validateToken(profile.Token); err != nil {
+		fmt.Fprintf(os.Stderr, "error: profile %s: %s\n", name, err.Error())
+		os.Exit(1)
+	}
+	return name, profile
+}
+
+func overlayString(val, override string) string {
+	if override != "" {
+		return override
+	}
+	return val
 }
 
 func retrieveUpdateSeconds(cmd *cobra.Command) int {
 	updateSecondsString := retrieveWithDefault(cmd, updateSecondsArg, "2")
 	updateSeconds, err := strconv.Atoi(updateSecondsString)
 	if err != nil {
-		fmt.Println(fmt.Errorf("update value %s cannot be converted to an integer", updateSecondsString))
+		fmt.Fprintln(os.Stderr, fmt.Errorf("update value %s cannot be converted to an integer", updateSecondsString))
 		os.Exit(1)
 	}
 	return updateSeconds
 }
 
+func retrieveUpdateJitter(cmd *cobra.Command) int {
+	updateJitterString := retrieveWithDefault(cmd, updateJitterArg, "5")
+	updateJitter, err := strconv.Atoi(updateJitterString)
+	if err != nil || updateJitter < 0 || updateJitter > 100 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("update jitter %s must be a percent from 0 to 100", updateJitterString))
+		os.Exit(1)
+	}
+	return updateJitter
+}
+
+func retrieveAPITimeout(cmd *cobra.Command) time.Duration {
+	apiTimeoutString := retrieveWithDefault(cmd, apiTimeoutArg, "30")
+	apiTimeout, err := strconv.Atoi(apiTimeoutString)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("api timeout value %s cannot be converted to an integer", apiTimeoutString))
+		os.Exit(1)
+	}
+	return time.Second * time.Duration(apiTimeout)
+}
+
+func retrieveExitOnDisconnect(cmd *cobra.Command) time.Duration {
+	exitOnDisconnectString := retrieveWithDefault(cmd, exitOnDisconnectArg, "0")
+	if exitOnDisconnectString == "0" {
+		return 0
+	}
+	exitOnDisconnect, err := time.ParseDuration(exitOnDisconnectString)
+	if err != nil || exitOnDisconnect < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("exit on disconnect value %s must be a duration like 5m", exitOnDisconnectString))
+		os.Exit(1)
+	}
+	return exitOnDisconnect
+}
+
+func retrieveDimAfter(cmd *cobra.Command) time.Duration {
+	dimAfterString := retrieveWithDefault(cmd, dimAfterArg, "0")
+	if dimAfterString == "0" {
+		return 0
+	}
+	dimAfter, err := time.ParseDuration(dimAfterString)
+	if err != nil || dimAfter < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("dim after value %s must be a duration like 10m", dimAfterString))
+		os.Exit(1)
+	}
+	return dimAfter
+}
+
+func retrieveExportFormat(cmd *cobra.Command) nomad.ExportFormat {
+	format := nomad.ExportFormat(strings.ToLower(retrieveWithDefault(cmd, exportFormatArg, string(nomad.ExportJSON))))
+	if format != nomad.ExportJSON && format != nomad.ExportYAML {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("export format %s must be json or yaml", format))
+		os.Exit(1)
+	}
+	return format
+}
+
+func retrieveIcons(cmd *cobra.Command) nomad.IconSet {
+	icons := nomad.IconSet(strings.ToLower(retrieveWithDefault(cmd, iconsArg, string(nomad.NoIcons))))
+	if icons != nomad.NoIcons && icons != nomad.ASCIIIcons && icons != nomad.NerdFontIcons {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("icons %s must be nerdfont, ascii or none", icons))
+		os.Exit(1)
+	}
+	return icons
+}
+
+func retrieveClipboardStrategies(cmd *cobra.Command) []app.ClipboardStrategy {
+	v := retrieveWithDefault(cmd, clipboardArg, "")
+	if v == "" {
+		return app.DefaultClipboardStrategies
+	}
+	var strategies []app.ClipboardStrategy
+	for _, s := range strings.Split(v, ",") {
+		strategy := app.ClipboardStrategy(strings.ToLower(strings.TrimSpace(s)))
+		if strategy != app.SystemClipboard && strategy != app.OSC52Clipboard {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("clipboard strategy %s must be system or osc52", strategy))
+			os.Exit(1)
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies
+}
+
+func retrieveLink(cmd *cobra.Command) *nomad.Link {
+	v := retrieveWithDefault(cmd, openArg, "")
+	if v == "" {
+		return nil
+	}
+	link, err := nomad.ParseLink(v)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return &link
+}
+
 func retrieveLogOffset(cmd *cobra.Command) int {
 	logOffsetString := retrieveWithDefault(cmd, logOffsetArg, "1000000")
 	logOffset, err := strconv.Atoi(logOffsetString)
 	if err != nil {
-		fmt.Println(fmt.Errorf("log offset %s cannot be converted to an integer", logOffsetString))
+		fmt.Fprintln(os.Stderr, fmt.Errorf("log offset %s cannot be converted to an integer", logOffsetString))
 		os.Exit(1)
 	}
 	return logOffset
 }
 
+func retrieveLogMaxLineLength(cmd *cobra.Command) int {
+	maxLengthString := retrieveWithDefault(cmd, logMaxLineLengthArg, "10000")
+	maxLength, err := strconv.Atoi(maxLengthString)
+	if err != nil || maxLength < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("log max line length %s must be a non-negative integer", maxLengthString))
+		os.Exit(1)
+	}
+	return maxLength
+}
+
+func retrieveLogCoalesce(cmd *cobra.Command) time.Duration {
+	coalesceString := retrieveWithDefault(cmd, logCoalesceArg, "50ms")
+	if coalesceString == "0" {
+		return 0
+	}
+	coalesce, err := time.ParseDuration(coalesceString)
+	if err != nil || coalesce < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("log coalesce value %s must be a duration like 100ms", coalesceString))
+		os.Exit(1)
+	}
+	return coalesce
+}
+
+func retrieveMaxSize(cmd *cobra.Command, a arg) int {
+	maxSizeString := retrieveWithDefault(cmd, a, "0")
+	maxSize, err := strconv.Atoi(maxSizeString)
+	if err != nil || maxSize < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("%s %s must be a non-negative integer", a.cliLong, maxSizeString))
+		os.Exit(1)
+	}
+	return maxSize
+}
+
+func retrieveMaxRows(cmd *cobra.Command) int {
+	maxRowsString := retrieveWithDefault(cmd, maxRowsArg, "auto")
+	if strings.ToLower(maxRowsString) == "auto" {
+		return 0
+	}
+	maxRows, err := strconv.Atoi(maxRowsString)
+	if err != nil || maxRows < 1 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("%s %s must be auto or a positive integer", maxRowsArg.cliLong, maxRowsString))
+		os.Exit(1)
+	}
+	return maxRows
+}
+
 // customLoggingMiddleware provides basic connection logging. Connects are logged with the
 // remote address, invoked command, TERM setting, window dimensions and if the
 // auth was public key based. Disconnect will log the remote address and
-// connection duration. It is custom because it excludes the ssh Command in the log.
+// connection duration. It is custom because it excludes the ssh Command, which may be a token, from the log, and
+// redacts token-shaped values, e.g. if a token is passed as the user.
 func customLoggingMiddleware() wish.Middleware {
 	return func(sh ssh.Handler) ssh.Handler {
 		return func(s ssh.Session) {
 			ct := time.Now()
 			hpk := s.PublicKey() != nil
 			pty, _, _ := s.Pty()
-			log.Printf("%s connect %s %v %v %v %v\n", s.User(), s.RemoteAddr().String(), hpk, pty.Term, pty.Window.Width, pty.Window.Height)
+			log.Print(dev.RedactIDs(fmt.Sprintf("%s connect %s %v %v %v %v\n", s.User(), s.RemoteAddr().String(), hpk, pty.Term, pty.Window.Width, pty.Window.Height)))
 			sh(s)
-			log.Printf("%s disconnect %s\n", s.RemoteAddr().String(), time.Since(ct))
+			log.Print(dev.RedactIDs(fmt.Sprintf("%s disconnect %s\n", s.RemoteAddr().String(), time.Since(ct))))
 		}
 	}
 }
 
-func setup(cmd *cobra.Command, overrideToken string) (app.Model, []tea.ProgramOption) {
+func getConfig(cmd *cobra.Command, overrideToken string) app.Config {
 	nomadAddr := retrieveAddress(cmd)
 	nomadToken := retrieveToken(cmd)
-	if overrideToken != "" {
-		err := validateToken(overrideToken)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-		nomadToken = overrideToken
-	}
 	region := retrieveRegion(cmd)
 	namespace := retrieveNamespace(cmd)
 	httpAuth := retrieveHTTPAuth(cmd)
@@ -273,18 +767,89 @@ func setup(cmd *cobra.Command, overrideToken string) (app.Model, []tea.ProgramOp
 	tlsServerName := retrieveTLSServerName(cmd)
 	skipVerify := retrieveSkipVerify(cmd)
 	logOffset := retrieveLogOffset(cmd)
+	logMaxLineLength := retrieveLogMaxLineLength(cmd)
+	logCoalesce := retrieveLogCoalesce(cmd)
+	defaultLogTasks := retrieveDefaultLogTasks()
+	logsFromStart := retrieveLogsFromStart(cmd)
+	maxWidth := retrieveMaxSize(cmd, maxWidthArg)
+	maxHeight := retrieveMaxSize(cmd, maxHeightArg)
+	maxTableRows := retrieveMaxRows(cmd)
+	showDatacenters := retrieveShowDatacenters(cmd)
+	icons := retrieveIcons(cmd)
+	hideDeadJobs := retrieveHideDeadJobs(cmd)
+	jobType := retrieveJobType(cmd)
+	jobsPerPage := retrieveMaxSize(cmd, jobsPerPageArg)
+	captureResponses := retrieveCaptureResponses(cmd)
+	logRedactions := retrieveLogRedactions()
 	copySavePath := retrieveCopySavePath(cmd)
+	exportFormat := retrieveExportFormat(cmd)
+	clipboardStrategies := retrieveClipboardStrategies(cmd)
+	nomadDataDir := retrieveWithDefault(cmd, nomadDataDirArg, "/opt/nomad/data")
+	filter := retrieveWithDefault(cmd, filterArg, "")
+	link := retrieveLink(cmd)
+	stateFile := retrieveStateFile(cmd)
+	readOnly := retrieveReadOnly(cmd)
+	confirmTyped := retrieveConfirmTyped()
+	sortByPage := retrieveSort()
 	eventTopics := retrieveEventTopics(cmd)
 	eventNamespace := retrieveEventNamespace(cmd)
 	eventJQQuery := retrieveEventJQQuery(cmd)
+	eventJQQueries := retrieveEventJQQueries()
+	eventOutFile := retrieveEventOutFile(cmd)
+	eventRotate := retrieveEventRotate(cmd)
 	updateSeconds := retrieveUpdateSeconds(cmd)
+	updateJitter := retrieveUpdateJitter(cmd)
+	apiTimeout := retrieveAPITimeout(cmd)
+	exitOnDisconnect := retrieveExitOnDisconnect(cmd)
+	dimAfter := retrieveDimAfter(cmd)
+	logo := retrieveNonCLIWithDefault(logoArg, "")
 	logoColor := retrieveNonCLIWithDefault(logoColorArg, "")
+	namespaceColors := viper.GetStringMapString(namespaceColorsArg.cfgFileEnvVar)
+	defaultNamespaceColor := retrieveNonCLIWithDefault(defaultNamespaceColorArg, "")
+	footerHints := retrieveFooterHints()
+	customActions := retrieveCustomActions()
+	watches := retrieveWatches()
+	highlightRules := retrieveHighlightRules()
+	var warnings []string
+	if !retrieveQuiet(cmd) {
+		warnings = append(getDeprecationWarnings(), getConfigFileWarnings()...)
+	}
 
-	initialModel := app.InitialModel(app.Config{
+	profileName, profile := retrieveProfile(cmd)
+	nomadAddr = overlayString(nomadAddr, profile.Addr)
+	nomadToken = overlayString(nomadToken, profile.Token)
+	region = overlayString(region, profile.Region)
+	namespace = overlayString(namespace, profile.Namespace)
+	httpAuth = overlayString(httpAuth, profile.HTTPAuth)
+	tokenFile, nomadToken := retrieveTokenFile(cmd, nomadToken)
+	// a token passed over ssh is the user's own, so takes precedence over the server's profile and token file
+	if overrideToken != "" {
+		err := validateToken(overrideToken)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+		nomadToken, tokenFile = overrideToken, ""
+	}
+	eventNamespace = overlayString(eventNamespace, profile.EventNamespace)
+	if profile.EventTopics != "" {
+		eventTopics = parseEventTopics(profile.EventTopics)
+	}
+	// the token's namespace is only a default, so anything naming a namespace takes precedence
+	namespaceFromToken := retrieveNamespaceFromToken(cmd) && retrieveWithDefault(cmd, namespaceArg, "") == "" && profile.Namespace == ""
+	if link != nil {
+		namespaceFromToken = namespaceFromToken && link.Namespace == ""
+		// the link describes the whole view, so its filter replaces the startup filter rather than adding to it
+		region = overlayString(region, link.Region)
+		namespace = overlayString(namespace, link.Namespace)
+		filter = link.Filter
+	}
+
+	return app.Config{
 		Version:   Version,
 		SHA:       CommitSHA,
 		URL:       nomadAddr,
 		Token:     nomadToken,
+		TokenFile: tokenFile,
 		Region:    region,
 		Namespace: namespace,
 		HTTPAuth:  httpAuth,
@@ -296,19 +861,104 @@ func setup(cmd *cobra.Command, overrideToken string) (app.Model, []tea.ProgramOp
 			ServerName: tlsServerName,
 			SkipVerify: skipVerify,
 		},
-		LogOffset:    logOffset,
-		CopySavePath: copySavePath,
+		LogOffset:        logOffset,
+		LogMaxLineLength: logMaxLineLength,
+		LogCoalesce:      logCoalesce,
+		DefaultLogTasks:  defaultLogTasks,
+		LogsFromStart:    logsFromStart,
+		LogRedactions:    logRedactions,
+		MaxWidth:         maxWidth,
+		MaxHeight:        maxHeight,
+		MaxTableRows:     maxTableRows,
+		CopySavePath:     copySavePath,
+		ExportFormat:     exportFormat,
+		Icons:            icons,
+		NomadDataDir:     nomadDataDir,
+		Filter:           filter,
+		Link:             link,
+		StateFile:        stateFile,
+		ReadOnly:         readOnly,
 		Event: app.EventConfig{
-			Topics:    eventTopics,
-			Namespace: eventNamespace,
-			JQQuery:   eventJQQuery,
+			Topics:      eventTopics,
+			Namespace:   eventNamespace,
+			JQQuery:     eventJQQuery,
+			JQQueries:   eventJQQueries,
+			OutFile:     eventOutFile,
+			RotateBytes: eventRotate,
 		},
-		UpdateSeconds: time.Second * time.Duration(updateSeconds),
-		LogoColor:     logoColor,
-	})
+		UpdateSeconds:         time.Second * time.Duration(updateSeconds),
+		UpdateJitterPercent:   updateJitter,
+		APITimeout:            apiTimeout,
+		ExitOnDisconnect:      exitOnDisconnect,
+		DimAfter:              dimAfter,
+		ClipboardStrategies:   clipboardStrategies,
+		ShowDatacenters:       showDatacenters,
+		HideDeadJobs:          hideDeadJobs,
+		JobType:               jobType,
+		JobsPerPage:           jobsPerPage,
+		NamespaceFromToken:    namespaceFromToken,
+		CaptureResponses:      captureResponses,
+		ConfirmTyped:          confirmTyped,
+		Sort:                  sortByPage,
+		Logo:                  logo,
+		LogoColor:             logoColor,
+		NamespaceColors:       namespaceColors,
+		DefaultNamespaceColor: defaultNamespaceColor,
+		FooterHints:           footerHints,
+		CustomActions:         customActions,
+		Watches:               watches,
+		HighlightRules:        highlightRules,
+		ProfileName:           profileName,
+		Warnings:              warnings,
+	}
+}
+
+func setup(cmd *cobra.Command, overrideToken string, sshOutput io.Writer, sshEnviron []string) (app.Model, []tea.ProgramOption) {
+	config := getConfig(cmd, overrideToken)
+	config.SSHOutput = sshOutput
+	config.SSHEnviron = sshEnviron
+	dev.AddSecrets(configSecrets(config)...)
+	dev.AddSecretPatterns(config.LogRedactions...)
+	startDebugLog(cmd, config)
+	initialModel := app.InitialModel(config)
+	if retrieveNoAltScreen(cmd) {
+		return initialModel, nil
+	}
 	return initialModel, []tea.ProgramOption{tea.WithAltScreen()}
 }
 
+// configSecrets are the values in config that must never be logged
+func configSecrets(config app.Config) []string {
+	secrets := []string{config.Token}
+	if _, password, found := strings.Cut(config.HTTPAuth, ":"); found {
+		secrets = append(secrets, password)
+	}
+	return secrets
+}
+
+var debugLogOnce sync.Once
+
+// startDebugLog writes diagnostics to the debug log file if set. When serving over ssh, the file is opened by the first
+// session and shared by the rest.
+func startDebugLog(cmd *cobra.Command, config app.Config) {
+	path := retrieveWithDefault(cmd, debugLogArg, "")
+	if path == "" {
+		return
+	}
+	debugLogOnce.Do(func() {
+		level, err := dev.ParseLevel(retrieveWithDefault(cmd, debugLevelArg, "info"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err = dev.SetLogFile(path, level); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("could not open debug log: %w", err))
+			os.Exit(1)
+		}
+	})
+	dev.Info(fmt.Sprintf("wander %s connecting to %s, namespace %s, region %s", getVersion(), config.URL, config.Namespace, config.Region))
+}
+
 func getVersion() string {
 	if Version == "" {
 		return constants.NoVersionString
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/gliderlabs/ssh"
+	"log"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSession is an ssh session with just what customLoggingMiddleware uses
+type fakeSession struct {
+	ssh.Session
+	user string
+}
+
+func (s fakeSession) User() string {
+	return s.user
+}
+
+func (s fakeSession) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}
+}
+
+func (s fakeSession) PublicKey() ssh.PublicKey {
+	return nil
+}
+
+func (s fakeSession) Pty() (ssh.Pty, <-chan ssh.Window, bool) {
+	return ssh.Pty{Term: "xterm", Window: ssh.Window{Width: 80, Height: 24}}, nil, true
+}
+
+func TestCustomLoggingMiddlewareRedactsTokenAsUser(t *testing.T) {
+	token := "5b9fd0e1-6d2e-4a5f-9c3a-8f1e2d3c4b5a"
+
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(original)
+
+	handled := false
+	customLoggingMiddleware()(func(ssh.Session) { handled = true })(fakeSession{user: token})
+
+	if !handled {
+		t.Error("expected the wrapped handler to run")
+	}
+	logged := buf.String()
+	if strings.Contains(logged, token) {
+		t.Errorf("token leaked into connection log: %q", logged)
+	}
+	if !strings.Contains(logged, "connect 127.0.0.1:1234") || !strings.Contains(logged, "127.0.0.1:1234 disconnect") {
+		t.Errorf("expected connect and disconnect to be logged, got %q", logged)
+	}
+}
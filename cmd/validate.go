@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+type configValueKind int8
+
+const (
+	scalarValue configValueKind = iota
+	listValue
+	mapValue
+)
+
+func (k configValueKind) String() string {
+	switch k {
+	case scalarValue:
+		return "a single value"
+	case listValue:
+		return "a list"
+	case mapValue:
+		return "a map"
+	}
+	return "unknown"
+}
+
+// knownConfigKeys maps each config file key to the kind of value it takes
+func knownConfigKeys() map[string]configValueKind {
+	known := make(map[string]configValueKind)
+	for _, a := range []arg{
+		oldAddrArg, addrArg, oldTokenArg, tokenArg, tokenFileArg, regionArg, namespaceArg, httpAuthArg, cacertArg,
+		capathArg, clientCertArg, clientKeyArg, tlsServerNameArg, skipVerifyArg, updateSecondsArg, updateJitterArg, apiTimeoutArg, exitOnDisconnectArg, dimAfterArg,
+		logOffsetArg, logMaxLineLengthArg, logCoalesceArg, logsFromStartArg, hideDeadJobsArg, jobTypeArg, jobsPerPageArg, namespaceFromTokenArg, debugLogArg, debugLevelArg, captureResponsesArg, maxRowsArg, showDatacentersArg, iconsArg, noAltScreenArg, maxWidthArg, maxHeightArg, copySavePathArg, profileNameArg, quietArg, strictConfigArg, skipPreflightArg, skipScopeCheckArg, exportFormatArg, clipboardArg, batchArg, filterArg,
+		openArg, stateFileArg, readOnlyArg, eventTopicsArg, eventNamespaceArg, eventJQQueryArg, eventOutFileArg, eventRotateArg, eventCountArg,
+		nomadDataDirArg, nomadUIURLArg, keysFormatArg, logoArg, logoColorArg, defaultNamespaceColorArg, hostArg, portArg, hostKeyPathArg, hostKeyPEMArg,
+	} {
+		known[a.cfgFileEnvVar] = scalarValue
+	}
+	for _, a := range []arg{logRedactionsArg, defaultLogTaskArg, confirmTypedArg, footerHintsArg, customActionsArg, watchesArg, highlightRulesArg, eventJQQueriesArg} {
+		known[a.cfgFileEnvVar] = listValue
+	}
+	for _, a := range []arg{namespaceColorsArg, profilesArg, sortArg} {
+		known[a.cfgFileEnvVar] = mapValue
+	}
+	return known
+}
+
+// configFileProblems are unknown keys or values of the wrong kind in the config file, which viper otherwise ignores
+var configFileProblems []string
+
+// validateConfigFile checks the top level keys of the config file and the kinds of their values, naming any offending
+// key. Values themselves are validated as they are retrieved.
+func validateConfigFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	known := knownConfigKeys()
+	var problems []string
+	for _, k := range keys {
+		kind, exists := known[strings.ToLower(k)]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("unknown key %s in config file", k))
+			continue
+		}
+		if actual := getConfigValueKind(values[k]); values[k] != nil && actual != kind {
+			problems = append(problems, fmt.Sprintf("key %s in config file should be %s, not %s", k, kind, actual))
+		}
+	}
+	return problems, nil
+}
+
+func getConfigValueKind(v interface{}) configValueKind {
+	switch v.(type) {
+	case []interface{}:
+		return listValue
+	case map[string]interface{}:
+		return mapValue
+	}
+	return scalarValue
+}
+
+func getConfigFileWarnings() []string {
+	var warnings []string
+	for _, p := range configFileProblems {
+		warnings = append(warnings, "warning: "+p)
+	}
+	return warnings
+}
@@ -2,22 +2,83 @@ package dev
 
 import (
 	"fmt"
-	tea "github.com/charmbracelet/bubbletea"
 	"log"
 	"os"
+	"strings"
+	"sync"
 )
 
-var debugSet = os.Getenv("WANDER_DEBUG")
+type Level int
 
-// dev
-func Debug(msg string) {
-	if debugSet != "" {
-		f, err := tea.LogToFile("wander.log", "")
-		if err != nil {
+const (
+	LevelError Level = iota
+	LevelInfo
+	LevelDebug
+)
+
+var levelNames = map[Level]string{LevelError: "error", LevelInfo: "info", LevelDebug: "debug"}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func ParseLevel(s string) (Level, error) {
+	for l, name := range levelNames {
+		if strings.ToLower(s) == name {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("log level %s must be error, info or debug", s)
+}
+
+var (
+	mu       sync.Mutex
+	logger   *log.Logger
+	logLevel Level
+)
+
+func init() {
+	// WANDER_DEBUG predates the debug log flag, and logs everything to wander.log in the working directory
+	if os.Getenv("WANDER_DEBUG") != "" {
+		if err := SetLogFile("wander.log", LevelDebug); err != nil {
 			fmt.Println("fatal:", err)
 			os.Exit(1)
 		}
-		log.Printf("%q", msg)
-		defer f.Close()
 	}
 }
+
+// SetLogFile appends messages up to level to the file at path, redacted. The file stays open until the process exits.
+func SetLogFile(path string, level Level) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	logger = log.New(f, "", log.LstdFlags|log.Lmicroseconds)
+	logLevel = level
+	return nil
+}
+
+// Debug logs the flow of messages through the ui
+func Debug(msg string) {
+	logAt(LevelDebug, msg)
+}
+
+// Info logs what wander does, e.g. api requests
+func Info(msg string) {
+	logAt(LevelInfo, msg)
+}
+
+func Error(msg string) {
+	logAt(LevelError, msg)
+}
+
+func logAt(level Level, msg string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if logger == nil || level > logLevel {
+		return
+	}
+	logger.Printf("%-5s %q", level, Redact(msg))
+}
@@ -0,0 +1,73 @@
+package dev
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "<redacted>"
+
+var (
+	redactMu       sync.RWMutex
+	secrets        = make(map[string]bool)
+	secretPatterns []*regexp.Regexp
+
+	// uuidPattern matches values shaped like Nomad tokens, which are also the shape of ids like allocation ids
+	uuidPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	// tokenPattern matches token-shaped values labelled as tokens, e.g. in headers or query parameters
+	tokenPattern = regexp.MustCompile(`(?i)(token\S*?[=:\s]\s*"?)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+)
+
+// AddSecrets registers values, e.g. tokens and passwords, that Redact removes. Empty values are ignored.
+func AddSecrets(values ...string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	for _, v := range values {
+		if v != "" {
+			secrets[v] = true
+		}
+	}
+}
+
+// AddSecretPatterns registers patterns, e.g. the configured log redactions, whose matches Redact removes. Patterns
+// already registered are ignored.
+func AddSecretPatterns(patterns ...*regexp.Regexp) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	// each ssh session registers the same patterns
+	for _, p := range patterns {
+		if !hasPattern(p) {
+			secretPatterns = append(secretPatterns, p)
+		}
+	}
+}
+
+func hasPattern(pattern *regexp.Regexp) bool {
+	for _, p := range secretPatterns {
+		if p.String() == pattern.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact removes registered secrets, matches of registered patterns, and token-shaped values labelled as tokens. Every
+// logging path goes through it.
+func Redact(s string) string {
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+	for secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	for _, p := range secretPatterns {
+		s = p.ReplaceAllString(s, redacted)
+	}
+	return tokenPattern.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// RedactIDs is Redact that also removes every token-shaped value, for output that shouldn't contain ids, e.g. ssh
+// connection logs where a token could be passed as the user
+func RedactIDs(s string) string {
+	return uuidPattern.ReplaceAllString(Redact(s), redacted)
+}
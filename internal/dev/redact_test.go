@@ -0,0 +1,53 @@
+package dev
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const (
+	testToken = "5b9fd0e1-6d2e-4a5f-9c3a-8f1e2d3c4b5a"
+	testID    = "0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b"
+)
+
+func TestRedact(t *testing.T) {
+	AddSecrets("hunter2", "")
+	AddSecretPatterns(regexp.MustCompile(`password=\S+`))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"registered secret", "auth user:hunter2", "auth user:" + redacted},
+		{"secret pattern", "url?password=abc&x", "url?" + redacted},
+		{"token header", "X-Nomad-Token: " + testToken, "X-Nomad-Token: " + redacted},
+		{"token query parameter", "/v1/jobs?token=" + testToken, "/v1/jobs?token=" + redacted},
+		{"quoted token", `{"SecretID":"x","token": "` + testToken + `"}`, `{"SecretID":"x","token": "` + redacted + `"}`},
+		{"unlabelled id kept", "alloc " + testID + " started", "alloc " + testID + " started"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := Redact(test.input); actual != test.expected {
+				t.Errorf("Redact(%q) = %q, expected %q", test.input, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRedactIDs(t *testing.T) {
+	for _, input := range []string{
+		testToken + " connect 127.0.0.1:1234",
+		"alloc " + strings.ToUpper(testID),
+		"token=" + testToken,
+	} {
+		actual := RedactIDs(input)
+		if strings.Contains(strings.ToLower(actual), testToken) || strings.Contains(strings.ToLower(actual), testID) {
+			t.Errorf("RedactIDs(%q) = %q, still has a token-shaped value", input, actual)
+		}
+		if !strings.Contains(actual, redacted) {
+			t.Errorf("RedactIDs(%q) = %q, expected %s", input, actual, redacted)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package fileio
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotatingFile appends to the file at path, moving it aside to a timestamped file once it would exceed maxBytes.
+// A maxBytes of zero or less disables rotation.
+type RotatingFile struct {
+	path     string
+	maxBytes int64
+	size     int64
+	file     *os.File
+	writer   *bufio.Writer
+	mtx      sync.Mutex
+}
+
+func NewRotatingFile(path string, maxBytes int64) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxBytes: maxBytes}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.writer.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close flushes any buffered content and closes the underlying file
+func (r *RotatingFile) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.closeFile()
+}
+
+func (r *RotatingFile) open() error {
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file, r.writer, r.size = f, bufio.NewWriter(f), info.Size()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	extension := filepath.Ext(r.path)
+	rotatedPath := strings.TrimSuffix(r.path, extension) + "_" + time.Now().Format("20060102T150405.000") + extension
+	if err := os.Rename(r.path, rotatedPath); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) closeFile() error {
+	if err := r.writer.Flush(); err != nil {
+		return err
+	}
+	return r.file.Close()
+}
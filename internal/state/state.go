@@ -0,0 +1,44 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// State is persisted between runs of wander, unlike config which is only read
+type State struct {
+	// FilterHistory is the recently applied filters by page name, most recent last
+	FilterHistory map[string][]string `json:"filter_history"`
+}
+
+// Load reads the state at path, returning empty state if the file doesn't exist yet
+func Load(path string) (State, error) {
+	s := State{FilterHistory: make(map[string][]string)}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+	if err = json.Unmarshal(content, &s); err != nil {
+		return s, err
+	}
+	if s.FilterHistory == nil {
+		s.FilterHistory = make(map[string][]string)
+	}
+	return s, nil
+}
+
+func (s State) Save(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
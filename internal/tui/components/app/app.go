@@ -1,13 +1,18 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/nomad/api"
 	"github.com/itchyny/gojq"
 	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/fileio"
+	"github.com/robinovitch61/wander/internal/state"
+	"github.com/robinovitch61/wander/internal/tui/components/confirm"
 	"github.com/robinovitch61/wander/internal/tui/components/header"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
 	"github.com/robinovitch61/wander/internal/tui/constants"
@@ -16,7 +21,9 @@ import (
 	"github.com/robinovitch61/wander/internal/tui/message"
 	"github.com/robinovitch61/wander/internal/tui/nomad"
 	"github.com/robinovitch61/wander/internal/tui/style"
+	"io"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -30,27 +37,91 @@ type EventConfig struct {
 	Topics    nomad.Topics
 	Namespace string
 	JQQuery   *gojq.Code
+	// JQQueries are named alternatives to JQQuery that can be cycled through on events pages
+	JQQueries   []NamedJQQuery
+	OutFile     string
+	RotateBytes int64
+}
+
+type NamedJQQuery struct {
+	Name string
+	Code *gojq.Code
 }
 
 type Config struct {
 	Version, SHA                  string
 	URL, Token, Region, Namespace string
+	TokenFile                     string
 	HTTPAuth                      string
 	TLS                           TLSConfig
 	Event                         EventConfig
 	LogOffset                     int
-	CopySavePath                  bool
-	UpdateSeconds                 time.Duration
-	LogoColor                     string
+	LogsFromStart                 bool
+	LogRedactions                 []*regexp.Regexp
+	LogMaxLineLength              int
+	// LogCoalesce is how long to batch followed log lines for before rendering them, to smooth bursts
+	LogCoalesce           time.Duration
+	DefaultLogTasks       []*regexp.Regexp
+	MaxWidth, MaxHeight   int
+	MaxTableRows          int
+	ShowDatacenters       bool
+	HideDeadJobs          bool
+	JobType               string
+	JobsPerPage           int
+	NamespaceFromToken    bool
+	CaptureResponses      bool
+	NomadDataDir          string
+	ExportFormat          nomad.ExportFormat
+	Icons                 nomad.IconSet
+	Filter                string
+	StateFile             string
+	CopySavePath          bool
+	ClipboardStrategies   []ClipboardStrategy
+	ReadOnly              bool
+	UpdateSeconds         time.Duration
+	UpdateJitterPercent   int
+	APITimeout            time.Duration
+	ExitOnDisconnect      time.Duration
+	DimAfter              time.Duration
+	Logo                  string
+	LogoColor             string
+	FooterHints           []FooterHint
+	CustomActions         []CustomAction
+	Watches               []JobWatch
+	HighlightRules        []page.HighlightRule
+	Warnings              []string
+	ProfileName           string
+	NamespaceColors       map[string]string
+	DefaultNamespaceColor string
+	// Sort orders pages' rows as they load
+	Sort map[nomad.Page][]page.SortKey
+	// ConfirmTyped lists namespaces whose destructive actions are confirmed by typing the resource's name
+	ConfirmTyped []string
+	// Link is the view opened on startup instead of the jobs page, if set
+	Link *nomad.Link
+	// responses records api responses if CaptureResponses, shared by copies of the config
+	responses *responseRecorder
+	// SSHOutput is the ssh session's Output when serving over ssh, used to copy to the client's clipboard and notify it
+	SSHOutput io.Writer
+	// SSHEnviron is the ssh session's environment when serving over ssh, used for the client's terminal type
+	SSHEnviron []string
 }
 
 type Model struct {
 	config Config
+	// client is created once and shared by all pages, copies share its connection pool
 	client api.Client
 
 	header      header.Model
 	currentPage nomad.Page
 	pageModels  map[nomad.Page]*page.Model
+	confirm     confirm.Model
+	footerHints []footerHintBinding
+	warnings    []string
+	state       state.State
+
+	// customActions are config-defined commands run on the selected row
+	customActions []customActionBinding
 
 	jobID        string
 	jobNamespace string
@@ -58,11 +129,95 @@ type Model struct {
 	taskName     string
 	logline      string
 	logType      nomad.LogType
+	logsMerged   bool
+	// logsAllTasks shows the logs of every task in the allocation instead of only taskName's
+	logsAllTasks bool
+	nodeID       string
+	dispatchJob  *api.Job
 
-	updateID int
+	// taskEventsOldestFirst reverses the default newest first order of task events
+	taskEventsOldestFirst bool
+
+	// rolloutHealth is the health of each allocation on the rollout page by ID as of its last load, to toast changes
+	rolloutHealth          map[string]string
+	rolloutHealthyNotified bool
+
+	// lastInputAt is when a key was last pressed, to dim the view after DimAfter without input
+	lastInputAt time.Time
+	dimmed      bool
+
+	// watchedJobStates is the state of each watched job by key as of its last check, to notify of transitions
+	watchedJobStates map[string]nomad.JobState
+
+	// collapsedTaskGroups are the task groups on the allocations page shown as one summary row instead of a row per task
+	collapsedTaskGroups map[string]bool
+
+	// hideDeadJobs leaves dead jobs out of the jobs page
+	hideDeadJobs bool
+
+	// jobType narrows the jobs page to jobs of that type, or shows all types if empty
+	jobType string
+
+	// jobsPages is how many pages of jobs to fetch if they're paginated, increasing as the selection reaches the bottom
+	jobsPages         int
+	jobsMoreAvailable bool
+	jobsLoadingMore   bool
+	// jobsFetchID identifies the latest fetch of jobs, so responses to earlier ones, e.g. an update racing a load of
+	// more, are dropped
+	jobsFetchID int
+	// jobsAllFetched is set while every page of jobs is shown for a filter. Updates pause meanwhile, as fetching every
+	// page on each is what pagination avoids, so r refreshes them instead.
+	jobsAllFetched bool
+
+	// jobSpecSource shows the source the job was submitted with instead of its spec
+	jobSpecSource bool
+
+	// logsPrettyJSON expands JSON log lines into indented lines
+	logsPrettyJSON bool
+
+	// logsFromStart reads logs from the start rather than logOffset bytes back from the end
+	logsFromStart bool
+
+	// logOffset is how many bytes back from the end logs are loaded, growing as older logs are loaded
+	logOffset int
+
+	// logsStream follows the logs of a single task once loaded, with logsPartial the start of a line not yet ended
+	logsStream  nomad.LogsStream
+	logsPartial string
 
-	eventsStream nomad.EventsStream
-	event        string
+	evalID     string
+	evalStatus string
+	evalPollID int
+
+	// errorHistory is the most recent toasted errors, oldest first, shown in full on the errors page
+	errorHistory []nomad.ErrorRecord
+
+	// returnPages are the pages that the pages returning to their opener were opened from, most recent last, returned
+	// to in turn on back
+	returnPages []nomad.Page
+
+	aclReadable   bool
+	aclPolicyName string
+
+	// quotasAvailable is true if the cluster has quotas, which are Nomad Enterprise only, and the token can read them
+	quotasAvailable bool
+
+	// serverVersion is the connected Nomad's version, empty until detected or if it can't be, to disable pages it lacks
+	serverVersion string
+
+	updateID int
+	searchID int
+
+	eventsStream  nomad.EventsStream
+	event         string
+	eventsOutFile *fileio.RotatingFile
+	// eventJQQueryIdx is the index of the active query in getEventJQQueries
+	eventJQQueryIdx int
+	// eventsPaused buffers events in eventsBuffer rather than displaying them until resumed, dropping the oldest
+	// beyond constants.EventsPauseBufferMaxCount and counting them in eventsDropped
+	eventsPaused  bool
+	eventsBuffer  []page.Row
+	eventsDropped int
 
 	execWebSocket       *websocket.Conn
 	execPty             *os.File
@@ -70,26 +225,60 @@ type Model struct {
 	webSocketConnected  bool
 	lastCommandFinished struct{ stdOut, stdErr bool }
 
+	waitingForNomad   bool
+	connectionErr     error
+	connectionRetries int
+	connectionRetryID int
+	connectionDelay   time.Duration
+	// disconnectedSince is when requests to Nomad started failing, zero if the last request succeeded
+	disconnectedSince time.Time
+	exitErr           error
+
 	width, height int
-	initialized   bool
-	err           error
+	// terminalWidth and terminalHeight are the full terminal size, larger than width and height if clamped by config
+	terminalWidth, terminalHeight int
+	initialized                   bool
+	err                           error
 }
 
 func InitialModel(c Config) Model {
 	firstPage := nomad.JobsPage
+	footerHints := getFooterHintBindings(c.FooterHints, c.ReadOnly)
+	customActions := getCustomActionBindings(c.CustomActions, c.ReadOnly)
 	initialHeader := header.New(
-		constants.LogoString,
+		getLogo(c.Logo),
 		c.LogoColor,
 		c.URL,
+		c.ProfileName,
 		getVersionString(c.Version, c.SHA),
-		nomad.GetPageKeyHelp(firstPage, false, false, false, false, false, false, nomad.StdOut),
+		nomad.GetPageKeyHelp(firstPage, nomad.KeyHelpState{
+			ReadOnly:           c.ReadOnly,
+			LogsFromStart:      c.LogsFromStart,
+			DeadJobsHidden:     c.HideDeadJobs,
+			CapturingResponses: c.CaptureResponses,
+			FooterHints:        append(footerHintKeyBindings(footerHints), customActionKeyBindings(customActions, firstPage)...),
+		}),
 	)
 
+	initialHeader.SetBorderColor(getNamespaceColor(c, c.Namespace))
+	initialHeader.SetNamespace(c.Namespace)
+	if c.CaptureResponses {
+		c.responses = &responseRecorder{}
+	}
+
 	return Model{
-		config:      c,
-		header:      initialHeader,
-		currentPage: firstPage,
-		updateID:    nextUpdateID(),
+		config:        c,
+		header:        initialHeader,
+		currentPage:   firstPage,
+		footerHints:   footerHints,
+		customActions: customActions,
+		logOffset:     c.LogOffset,
+		logsFromStart: c.LogsFromStart,
+		hideDeadJobs:  c.HideDeadJobs,
+		jobType:       c.JobType,
+		jobsPages:     1,
+		warnings:      c.Warnings,
+		updateID:      nextUpdateID(),
 	}
 }
 
@@ -115,85 +304,469 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case tea.KeyMsg:
+		m.lastInputAt = time.Now()
+		if m.dimmed && msg.Type != tea.KeyCtrlC {
+			// the key only restores brightness, as whoever pressed it couldn't see the view clearly
+			m.dimmed = false
+			return m, nil
+		}
+
+		if m.waitingForNomad {
+			switch {
+			case key.Matches(msg, keymap.KeyMap.Exit):
+				return m, m.cleanupCmd()
+			case key.Matches(msg, keymap.KeyMap.Reload):
+				m.connectionRetryID = nextUpdateID()
+				return m, nomad.CheckConnection(m.client)
+			}
+			return m, nil
+		}
+
+		if m.confirm.Visible {
+			if msg.Type == tea.KeyCtrlC {
+				return m, m.cleanupCmd()
+			}
+			m.confirm, cmd = m.confirm.Update(msg)
+			return m, cmd
+		}
+
+		if len(m.warnings) > 0 && key.Matches(msg, keymap.KeyMap.Back) {
+			m.warnings = m.warnings[1:]
+			return m, nil
+		}
+
 		cmd = m.handleKeyMsg(msg)
 		if cmd != nil {
 			return m, cmd
 		}
 
+	case message.ToastMsg:
+		if msg.Err != nil {
+			m.recordError(msg.Err)
+		}
+
+	case dimCheckMsg:
+		if time.Since(m.lastInputAt) >= m.config.DimAfter {
+			m.dimmed = true
+		}
+		return m, checkDimWithDelay()
+
+	case watchCheckMsg:
+		return m, m.fetchWatchedJobStates()
+
+	case nomad.JobStatesMsg:
+		notifications := m.notifyWatchTransitions(msg.States)
+		return m, tea.Batch(notifications, checkWatchesWithDelay(constants.WatchCheckInterval))
+
+	case disconnectCheckMsg:
+		if !m.disconnectedSince.IsZero() && time.Since(m.disconnectedSince) > m.config.ExitOnDisconnect {
+			m.exitErr = fmt.Errorf("nomad at %s unreachable for over %s", m.config.URL, m.config.ExitOnDisconnect)
+			return m, m.cleanupCmd()
+		}
+		return m, checkDisconnectWithDelay()
+
 	case message.ErrMsg:
+		dev.Error(fmt.Sprintf("%s: %v", m.currentPage, msg.Err))
+		if isNetworkError(msg.Err) {
+			m.setDisconnected()
+		}
+		if m.initialized && isTimeout(msg.Err) {
+			// keep the ui responsive and try again on the next update rather than showing a fatal error
+			m.getCurrentPageModel().SetLoading(false)
+			cmds = append(cmds, toastCmd(message.ToastMsg{Err: fmt.Errorf("request timed out after %s, r to reload", m.config.APITimeout)}))
+			cmds = append(cmds, nomad.UpdatePageDataWithDelay(m.updateID, m.currentPage, withJitter(m.config.UpdateSeconds, m.config.UpdateJitterPercent)))
+			return m, tea.Batch(cmds...)
+		}
 		m.err = msg
 		return m, nil
 
 	case tea.WindowSizeMsg:
-		m.width, m.height = msg.Width, msg.Height
+		m.terminalWidth, m.terminalHeight = msg.Width, msg.Height
+		m.width, m.height = clampSize(msg.Width, m.config.MaxWidth), clampSize(msg.Height, m.config.MaxHeight)
+		m.header.SetWidth(m.width)
 		if !m.initialized {
-			err := m.initialize()
+			linkCmd, err := m.initialize()
 			if err != nil {
 				m.err = err
 				return m, nil
 			}
-			cmds = append(cmds, m.getCurrentPageCmd())
+			cmds = append(cmds, linkCmd)
+			cmds = append(cmds, nomad.CheckConnection(m.client))
+			if m.config.ExitOnDisconnect > 0 {
+				cmds = append(cmds, checkDisconnectWithDelay())
+			}
+			if m.config.DimAfter > 0 {
+				m.lastInputAt = time.Now()
+				cmds = append(cmds, checkDimWithDelay())
+			}
+			if len(m.config.Watches) > 0 {
+				cmds = append(cmds, m.fetchWatchedJobStates())
+			}
 		} else {
 			m.setPageWindowSize()
+			m.confirm.SetWidth(m.width)
 			if m.currentPage == nomad.ExecPage {
 				viewportHeightWithoutFooter := m.getCurrentPageModel().ViewportHeight() - 1 // hardcoded as known today, has to change if footer expands
 				cmds = append(cmds, nomad.ResizeTty(m.execWebSocket, m.width, viewportHeightWithoutFooter))
 			}
 		}
 
+	case nomad.ConnectionCheckedMsg:
+		if msg.Err != nil {
+			m.setDisconnected()
+			m.waitingForNomad, m.connectionErr = true, msg.Err
+			m.connectionDelay = retryDelay(m.connectionRetries)
+			m.connectionRetries++
+			m.connectionRetryID = nextUpdateID()
+			return m, nomad.RetryConnectionWithDelay(m.connectionRetryID, m.connectionDelay)
+		}
+		m.waitingForNomad, m.connectionErr, m.connectionRetries = false, nil, 0
+		m.disconnectedSince = time.Time{}
+		cmds = append(cmds, m.getCurrentPageCmd())
+		cmds = append(cmds, nomad.CheckACLAccess(m.client))
+		cmds = append(cmds, nomad.CheckQuotas(m.client))
+		cmds = append(cmds, nomad.FetchServerVersion(m.client))
+		if m.config.NamespaceFromToken {
+			cmds = append(cmds, nomad.FetchTokenNamespace(m.client))
+		}
+
+	case linkedAllocationMsg:
+		m.showLinkedAllocation(msg)
+		if msg.err == nil {
+			m.updateID = nextUpdateID()
+			cmds = append(cmds, m.getCurrentPageCmd())
+		}
+
+	case customActionFinishedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, toastCmd(message.ToastMsg{Err: fmt.Errorf("%s: %w", msg.label, msg.err)}))
+		} else {
+			cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Ran %s", msg.label)}))
+		}
+		// the action may have changed what the page shows
+		if m.currentPage.DoesReload() {
+			m.updateID = nextUpdateID()
+			cmds = append(cmds, m.getCurrentPageCmd())
+		}
+
+	case nomad.ACLAccessCheckedMsg:
+		m.aclReadable = msg.CanRead
+
+	case nomad.QuotasCheckedMsg:
+		m.quotasAvailable = msg.Available
+
+	case nomad.ServerVersionMsg:
+		m.serverVersion = msg.Version
+		m.header.SetServerVersion(msg.Version)
+		m.setPageWindowSize()
+
+	case nomad.TokenNamespaceMsg:
+		// only on first connecting, so it doesn't undo namespace changes
+		m.config.NamespaceFromToken = false
+		if msg.Namespace != "" && msg.Namespace != m.config.Namespace {
+			cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Showing namespace %s, the only one your token grants", msg.Namespace)}))
+			cmds = append(cmds, func() tea.Msg { return nomad.NamespaceCycledMsg{Namespace: msg.Namespace} })
+		}
+
+	case nomad.NamespaceCycledMsg:
+		m.config.Namespace = msg.Namespace
+		m.client.SetNamespace(msg.Namespace)
+		m.header.SetNamespace(msg.Namespace)
+		m.header.SetBorderColor(m.getActiveNamespaceColor())
+		m.jobsPages = 1
+		if m.currentPage == nomad.JobsPage {
+			m.updateID = nextUpdateID()
+			m.getCurrentPageModel().SetLoading(true)
+			cmds = append(cmds, m.getCurrentPageCmd())
+		}
+
+	case nomad.RetryConnectionMsg:
+		if msg.ID == m.connectionRetryID {
+			return m, nomad.CheckConnection(m.client)
+		}
+		return m, nil
+
 	case nomad.PageLoadedMsg:
+		if msg.Page == nomad.JobsPage && msg.FetchID != m.jobsFetchID {
+			return m, nil
+		}
+		m.disconnectedSince = time.Time{}
 		if msg.Page == m.currentPage {
 			m.getCurrentPageModel().SetHeader(msg.TableHeader)
 			m.getCurrentPageModel().SetAllPageData(msg.AllPageRows)
-			if m.currentPageLoading() {
+			firstLoad := m.currentPageLoading()
+			if firstLoad {
 				m.getCurrentPageModel().SetViewportXOffset(0)
 			}
 			m.getCurrentPageModel().SetLoading(false)
 
 			switch m.currentPage {
 			case nomad.JobsPage:
-				if m.currentPage == nomad.JobsPage && len(msg.AllPageRows) == 0 {
+				m.jobsMoreAvailable, m.jobsLoadingMore = msg.MoreAvailable, false
+				m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+				if m.currentPage == nomad.JobsPage && len(msg.AllPageRows) == 0 && !msg.MoreAvailable {
 					// oddly, nomad http api errors when one provides the wrong token, but returns empty results when one provides an empty token
+					noJobs := "No job results. Is the cluster empty or no nomad token provided?"
+					if m.jobType != "" {
+						noJobs = fmt.Sprintf("No %s jobs. Press t to show other job types.", m.jobType)
+					} else if m.hideDeadJobs {
+						noJobs = "No job results. Dead jobs are hidden, press i to show them."
+					}
 					m.getCurrentPageModel().SetAllPageData([]page.Row{
-						{"", "No job results. Is the cluster empty or no nomad token provided?"},
-						{"", "Press q or ctrl+c to quit."},
+						{Row: noJobs},
+						{Row: "Press q or ctrl+c to quit."},
 					})
 					m.getCurrentPageModel().SetViewportSelectionEnabled(false)
 				}
 			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
 				m.eventsStream = msg.Connection
-				cmds = append(cmds, nomad.ReadEventsStreamNextMessage(m.eventsStream, m.config.Event.JQQuery))
+				cmds = append(cmds, m.readEventsStream())
+			case nomad.AllocationsPage:
+				if firstLoad {
+					if idx := nomad.PreferredTaskRowIdx(m.getCurrentPageModel().FilteredPageRows(), m.config.DefaultLogTasks); idx >= 0 {
+						m.getCurrentPageModel().SetViewportSelection(idx)
+					}
+				}
 			case nomad.LogsPage:
 				m.getCurrentPageModel().SetViewportSelectionToBottom()
+				cmds = append(cmds, m.followLogs())
+			case nomad.SearchPage:
+				m.getCurrentPageModel().SetViewportSelectionEnabled(len(msg.AllPageRows) > 0)
+				if len(msg.AllPageRows) == 0 {
+					hint := "Press / and type an ID prefix to search jobs, allocations, and nodes."
+					if query := m.getCurrentPageModel().FilterValue(); query != "" {
+						hint = fmt.Sprintf("No results for %s", query)
+					}
+					m.getCurrentPageModel().SetAllPageData([]page.Row{{Row: hint}})
+				}
 			case nomad.ExecPage:
 				m.getCurrentPageModel().SetInputPrefix("Enter command: ")
+			case nomad.DispatchPage:
+				m.getCurrentPageModel().SetInputPrefix(nomad.DispatchInputPrefix(m.dispatchJob))
+				m.getCurrentPageModel().SetInputValue(nomad.DispatchInputTemplate(m.dispatchJob))
+			case nomad.EvaluationPage:
+				cmds = append(cmds, nomad.PollEvaluation(m.client, m.evalID, m.jobNamespace))
+			case nomad.JobRolloutPage:
+				var transitions []string
+				var allHealthy bool
+				m.rolloutHealth, transitions, allHealthy = nomad.RolloutTransitions(m.rolloutHealth, msg.AllPageRows)
+				if allHealthy && !m.rolloutHealthyNotified {
+					m.rolloutHealthyNotified = true
+					cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("All %d allocations of %s are healthy", len(msg.AllPageRows), m.jobID)}))
+				} else if len(transitions) > 0 {
+					cmds = append(cmds, toastCmd(message.ToastMsg{Message: "Now " + strings.Join(transitions, ", ")}))
+				}
+			}
+			if m.currentPage != nomad.JobsPage || !m.jobsAllFetched {
+				cmds = append(cmds, nomad.UpdatePageDataWithDelay(m.updateID, m.currentPage, withJitter(m.config.UpdateSeconds, m.config.UpdateJitterPercent)))
 			}
-			cmds = append(cmds, nomad.UpdatePageDataWithDelay(m.updateID, m.currentPage, m.config.UpdateSeconds))
 		}
 
 	case nomad.EventsStreamMsg:
 		if m.currentPage == nomad.JobEventsPage || m.currentPage == nomad.AllocEventsPage || m.currentPage == nomad.AllEventsPage {
 			if fmt.Sprint(msg.Topics) == fmt.Sprint(m.eventsStream.Topics) && msg.CompleteValue != "{}" {
-				scrollDown := m.getCurrentPageModel().ViewportSelectionAtBottom()
-				m.getCurrentPageModel().AppendToViewport([]page.Row{{Key: msg.CompleteValue, Row: msg.JQValue}}, true)
-				if scrollDown {
-					m.getCurrentPageModel().ScrollViewportToBottom()
+				row := page.Row{Key: msg.CompleteValue, Row: msg.JQValue}
+				if m.eventsPaused {
+					m.eventsBuffer = append(m.eventsBuffer, row)
+					if excess := len(m.eventsBuffer) - constants.EventsPauseBufferMaxCount; excess > 0 {
+						m.eventsBuffer = m.eventsBuffer[excess:]
+						m.eventsDropped += excess
+					}
+					m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+				} else {
+					m.appendStreamedRows([]page.Row{row})
+				}
+				if m.eventsOutFile != nil {
+					if _, err := m.eventsOutFile.Write([]byte(msg.JQValue + "\n")); err != nil {
+						m.err = err
+						return m, nil
+					}
+				}
+			}
+			cmds = append(cmds, m.readEventsStream())
+		}
+
+	case nomad.LogsFollowedMsg:
+		if msg.Stream.ID != m.logsStream.ID || m.currentPage != nomad.LogsPage {
+			msg.Stream.Close()
+			return m, nil
+		}
+		m.logsStream = msg.Stream
+		cmds = append(cmds, m.readLogsStream())
+
+	case nomad.LogsStreamMsg:
+		if msg.ID != m.logsStream.ID || m.currentPage != nomad.LogsPage {
+			return m, nil
+		}
+		m.logsPartial = msg.Partial
+		if len(msg.Rows) > 0 {
+			m.appendStreamedRows(msg.Rows)
+		}
+		if msg.Err != nil {
+			cmds = append(cmds, toastCmd(message.ToastMsg{Err: fmt.Errorf("stopped following logs: %w", msg.Err)}))
+		}
+		if !msg.Done {
+			cmds = append(cmds, m.readLogsStream())
+		}
+
+	case nomad.EventsStreamErrMsg:
+		if m.currentPage == nomad.JobEventsPage || m.currentPage == nomad.AllocEventsPage || m.currentPage == nomad.AllEventsPage {
+			if fmt.Sprint(msg.Topics) != fmt.Sprint(m.eventsStream.Topics) {
+				// a stale stream from a previous page
+				return m, nil
+			}
+			return m, m.resubscribeToEvents(msg.Err)
+		}
+
+	case pagerReadyMsg:
+		return m, openInPager(msg.path, m.config.SSHOutput != nil, m.config.SSHEnviron)
+
+	case pagerFinishedMsg:
+		return m, pagerFinished(msg)
+
+	case nomad.JobSpecReadyForEditMsg:
+		return m, nomad.EditJobSpec(msg.Path)
+
+	case nomad.JobSpecEditedMsg:
+		if msg.Err != nil {
+			_ = os.Remove(msg.Path)
+			return m, toastCmd(message.ToastMsg{Err: msg.Err})
+		}
+		return m, nomad.PlanEditedJobSpec(m.client, msg.Path, m.jobNamespace)
+
+	case nomad.JobPlannedMsg:
+		prompt := fmt.Sprintf("Submit %s? %s", *msg.Job.ID, msg.Summary)
+		m.confirm = m.newDestructiveConfirm(prompt, *msg.Job.ID, nomad.RegisterJob(m.client, msg.Job, m.jobNamespace, msg.JobModifyIndex))
+		return m, nil
+
+	case nomad.JobRegisteredMsg:
+		cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Submitted %s, evaluation %s", msg.JobID, formatter.ShortAllocID(msg.EvalID))}))
+		cmds = append(cmds, m.followEvaluation(msg.EvalID))
+
+	case nomad.AllocationStoppedMsg:
+		cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Stopped allocation %s, evaluation %s", formatter.ShortAllocID(msg.AllocID), formatter.ShortAllocID(msg.EvalID))}))
+		cmds = append(cmds, m.followEvaluation(msg.EvalID))
+
+	case nomad.AllocationsExportedMsg:
+		cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Exported %d allocations to %s", msg.Count, msg.Path)}))
+		if m.config.CopySavePath {
+			cmds = append(cmds, m.copyCmd(msg.Path, msg.Path))
+		}
+
+	case message.CopyMsg:
+		cmds = append(cmds, m.copyCmd(msg.Text, msg.Description))
+
+	case nomad.RollingRestartMsg:
+		if msg.Restarted == len(msg.AllocIDs) {
+			cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Restarted all %d allocations of %s", msg.Restarted, msg.JobID)}))
+		} else {
+			if msg.Restarted > 0 {
+				cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Restarted %d of %d allocations of %s", msg.Restarted, len(msg.AllocIDs), msg.JobID)}))
+			}
+			cmds = append(cmds, nomad.RestartNextAllocation(m.client, msg))
+		}
+
+	case nomad.JobEvaluatedMsg:
+		cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Created evaluation %s for %s", formatter.ShortAllocID(msg.EvalID), msg.JobID)}))
+		cmds = append(cmds, m.followEvaluation(msg.EvalID))
+
+	case nomad.PollEvaluationMsg:
+		if msg.ID == m.evalPollID && msg.EvalID == m.evalID && m.currentPage == nomad.EvaluationPage {
+			cmds = append(cmds, nomad.PollEvaluation(m.client, m.evalID, m.jobNamespace))
+		}
+
+	case nomad.EvaluationPolledMsg:
+		if msg.EvalID == m.evalID && m.currentPage == nomad.EvaluationPage {
+			if msg.Err != nil {
+				// only shown once until it recovers, by using the status to track it
+				if m.evalStatus != evalPollErrorStatus {
+					m.evalStatus = evalPollErrorStatus
+					m.getCurrentPageModel().AppendToViewport([]page.Row{nomad.EvaluationPollErrorRow(m.evalID, msg.Err)}, true)
+				}
+				cmds = append(cmds, nomad.PollEvaluationWithDelay(m.evalPollID, m.evalID))
+				break
+			}
+			if msg.Eval.Status != m.evalStatus {
+				m.evalStatus = msg.Eval.Status
+				m.getCurrentPageModel().AppendToViewport([]page.Row{nomad.EvaluationStatusRow(msg.Eval)}, true)
+			}
+			if !nomad.EvaluationDone(msg.Eval) {
+				cmds = append(cmds, nomad.PollEvaluationWithDelay(m.evalPollID, m.evalID))
+			} else if nomad.EvaluationSucceeded(msg.Eval) && m.peekReturnPage() != nomad.JobEvaluationsPage {
+				m.setPage(m.popReturnPage())
+				cmds = append(cmds, m.getCurrentPageCmd())
+				cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Evaluation %s complete", formatter.ShortAllocID(m.evalID))}))
+			} else {
+				// stay on the page so the blocked eval chain and placement failures can be read, or if the evaluation was
+				// selected to inspect it
+				m.getCurrentPageModel().AppendToViewport(nomad.EvaluationResultRows(msg.Eval), true)
+				if next := nomad.NextEvaluationInChain(msg.Eval); next != "" {
+					m.evalID, m.evalStatus = next, ""
+					m.getCurrentPageModel().AppendToViewport([]page.Row{{Row: fmt.Sprintf("Following evaluation %s", next)}}, true)
+					cmds = append(cmds, nomad.PollEvaluationWithDelay(m.evalPollID, m.evalID))
 				}
 			}
-			cmds = append(cmds, nomad.ReadEventsStreamNextMessage(m.eventsStream, m.config.Event.JQQuery))
+		}
+
+	case message.FilterHistoryChangedMsg:
+		if m.config.StateFile != "" && m.state.FilterHistory != nil {
+			m.state.FilterHistory[m.currentPage.String()] = msg.History
+			cmds = append(cmds, saveStateCmd(m.state, m.config.StateFile))
+		}
+		if m.currentPage == nomad.JobsPage && nomad.FilterUsesMeta(m.getCurrentPageModel().FilterValue()) {
+			// the loaded jobs may not include meta, which is only fetched when filtered on
+			cmds = append(cmds, m.getCurrentPageCmd())
+		}
+
+	case nomad.SearchDebounceMsg:
+		if msg.ID == m.searchID && m.currentPage == nomad.SearchPage {
+			cmds = append(cmds, nomad.FetchSearchResults(m.client, msg.Query))
 		}
 
 	case nomad.UpdatePageDataMsg:
-		if msg.ID == m.updateID && msg.Page == m.currentPage {
+		// loading more jobs refreshes those already loaded too, and schedules the next update when done
+		if msg.ID == m.updateID && msg.Page == m.currentPage && !(m.currentPage == nomad.JobsPage && m.jobsLoadingMore) {
 			cmds = append(cmds, m.getCurrentPageCmd())
 			m.updateID = nextUpdateID()
 		}
 
 	case message.PageInputReceivedMsg:
-		if m.currentPage == nomad.ExecPage {
+		switch m.currentPage {
+		case nomad.ExecPage:
 			m.getCurrentPageModel().SetLoading(true)
 			return m, nomad.InitiateWebSocket(m.config.URL, m.config.Token, m.alloc.ID, m.taskName, msg.Input)
+		case nomad.DispatchPage:
+			meta, payload, err := nomad.ParseDispatchInput(msg.Input, m.dispatchJob)
+			if err != nil {
+				m.requestDispatchInputAgain(err)
+				return m, nil
+			}
+			m.getCurrentPageModel().SetLoading(true)
+			return m, nomad.DispatchJob(m.client, m.jobID, m.jobNamespace, meta, payload)
+		}
+
+	case nomad.ParameterizedJobFetchedMsg:
+		m.dispatchJob = msg.Job
+		m.setPage(nomad.DispatchPage)
+		m.getCurrentPageModel().SetDoesNeedNewInput()
+		return m, m.getCurrentPageCmd()
+
+	case nomad.JobDispatchedMsg:
+		if m.currentPage == nomad.DispatchPage {
+			m.getCurrentPageModel().SetLoading(false)
+			// return to jobs once the dispatch evaluation is followed, as the dispatch input is complete
+			m.setPage(nomad.JobsPage)
+		}
+		cmds = append(cmds, toastCmd(message.ToastMsg{Message: fmt.Sprintf("Dispatched %s", msg.DispatchedJobID)}))
+		cmds = append(cmds, m.followEvaluation(msg.EvalID))
+		return m, tea.Batch(cmds...)
+
+	case nomad.JobDispatchFailedMsg:
+		if m.currentPage == nomad.DispatchPage {
+			m.getCurrentPageModel().SetLoading(false)
+			m.requestDispatchInputAgain(msg.Err)
 		}
+		return m, nil
 
 	case nomad.ExecWebSocketConnectedMsg:
 		m.execWebSocket = msg.WebSocketConnection
@@ -231,8 +804,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	currentPageModel = m.getCurrentPageModel()
 	if currentPageModel != nil && !currentPageModel.EnteringInput() {
+		prevFilter := currentPageModel.FilterValue()
 		*currentPageModel, cmd = currentPageModel.Update(msg)
 		cmds = append(cmds, cmd)
+		if m.currentPage == nomad.SearchPage && currentPageModel.FilterValue() != prevFilter {
+			m.searchID = nextUpdateID()
+			cmds = append(cmds, nomad.SearchWithDelay(m.searchID, currentPageModel.FilterValue()))
+		}
+		if m.currentPage == nomad.JobsPage {
+			cmds = append(cmds, m.loadMoreJobs(currentPageModel.FilterValue() != prevFilter))
+		}
 	}
 	m.updateKeyHelp()
 
@@ -244,35 +825,236 @@ func (m Model) View() string {
 		return fmt.Sprintf("Error: %v", m.err) + "\n\nif this seems wrong, consider opening an issue here: https://github.com/robinovitch61/wander/issues/new/choose" + "\n\nq/ctrl+c to quit"
 	} else if !m.initialized {
 		return ""
+	} else if m.waitingForNomad {
+		return fmt.Sprintf("Waiting for Nomad at %s...", m.config.URL) +
+			fmt.Sprintf("\n\n%v", m.connectionErr) +
+			fmt.Sprintf("\n\nattempt %d, retrying in %s", m.connectionRetries, m.connectionDelay) +
+			"\n\nr to retry now, q/ctrl+c to quit"
 	}
 
 	pageView := m.header.View() + "\n" + m.getCurrentPageModel().View()
 
+	if m.confirm.Visible {
+		pageView = overlayBottom(pageView, m.confirm.View())
+	} else if len(m.warnings) > 0 {
+		pageView = overlayBottom(pageView, style.Warning.Copy().Width(m.width).Render(m.warnings[0]+" (esc to dismiss)"))
+	} else if lastError, exists := m.selectedAllocationLastError(); exists {
+		pageView = overlayBottom(pageView, style.LastErrorPanel.Copy().Width(m.width).Render(lastError))
+	}
+
+	if m.dimmed {
+		pageView = style.Dimmed.Render(formatter.StripANSI(pageView))
+	}
+
+	if m.width < m.terminalWidth || m.height < m.terminalHeight {
+		return lipgloss.Place(m.terminalWidth, m.terminalHeight, lipgloss.Center, lipgloss.Center, pageView)
+	}
 	return pageView
 }
 
-func (m *Model) initialize() error {
-	client, err := m.config.client()
+// selectedAllocationLastError is the last error of the selected allocation's task if it failed
+func (m Model) selectedAllocationLastError() (string, bool) {
+	if m.currentPage != nomad.AllocationsPage || m.currentPageLoading() {
+		return "", false
+	}
+	selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow()
+	if err != nil {
+		return "", false
+	}
+	allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+	if err != nil {
+		return "", false
+	}
+	return nomad.LastAllocationError(allocInfo.Alloc, allocInfo.TaskName)
+}
+
+// initialize sets up the model once the terminal size is known, returning a command to open any link that needs data
+// from Nomad first
+func (m *Model) initialize() (tea.Cmd, error) {
+	client, err := m.config.Client()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	m.client = *client
 
+	if m.config.Event.OutFile != "" {
+		m.eventsOutFile, err = fileio.NewRotatingFile(m.config.Event.OutFile, m.config.Event.RotateBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	m.pageModels = make(map[nomad.Page]*page.Model)
-	for k, c := range nomad.GetAllPageConfigs(m.width, m.getPageHeight(), m.config.CopySavePath) {
+	for k, c := range nomad.GetAllPageConfigs(m.width, m.getPageHeight(), m.config.CopySavePath, m.config.HighlightRules, m.config.MaxTableRows) {
+		c.Sort = m.config.Sort[k]
 		p := page.New(c)
 		m.pageModels[k] = &p
 	}
 
+	if m.config.StateFile != "" {
+		// state is a convenience, so continue with empty state if it can't be read
+		m.state, _ = state.Load(m.config.StateFile)
+		for k, pm := range m.pageModels {
+			pm.SetFilterHistory(m.state.FilterHistory[k.String()])
+		}
+	}
+	var linkCmd tea.Cmd
+	if m.config.Link != nil {
+		linkCmd = m.openLink(*m.config.Link)
+	}
+	m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+	if m.config.Filter != "" {
+		m.getCurrentPageModel().SetFilter(m.config.Filter)
+	}
+
 	m.initialized = true
+	return linkCmd, nil
+}
+
+// Snapshot loads the first page and renders it once without the interactive program, e.g. for dashboards
+func (m Model) Snapshot(width, height int) (string, error) {
+	m.config.Event.OutFile = ""
+	m.warnings = nil
+	m.width, m.height = width, height
+	linkCmd, err := m.initialize()
+	if err != nil {
+		return "", err
+	}
+	if linkCmd != nil {
+		m.showLinkedAllocation(linkCmd().(linkedAllocationMsg))
+	}
+
+	msg := m.getCurrentPageCmd()()
+	switch msg := msg.(type) {
+	case message.ErrMsg:
+		return "", msg.Err
+	case message.ToastMsg:
+		if msg.Err != nil {
+			return "", msg.Err
+		}
+	}
+
+	updated, _ := m.Update(msg)
+	return updated.View(), nil
+}
+
+// refreshToken re-reads the token file after an auth failure so long-lived connections survive token rotation,
+// returning an error describing why the connection can't continue otherwise
+func (m *Model) refreshToken(authErr error) error {
+	if !nomad.IsAuthError(authErr) || m.config.TokenFile == "" {
+		return authErr
+	}
+	token, err := nomad.ReadTokenFile(m.config.TokenFile)
+	if err != nil {
+		return fmt.Errorf("%w, and re-reading token file failed: %v", authErr, err)
+	}
+	if token == m.config.Token {
+		return fmt.Errorf("%w, and the token in %s is unchanged", authErr, m.config.TokenFile)
+	}
+	m.config.Token = token
+	m.client.SetSecretID(token)
 	return nil
 }
 
+// copyID copies the ID of the selected job or allocation, or of the node shown, shortened to 8 characters if short.
+// Job IDs are names, so are never shortened.
+func (m Model) copyID(short bool) tea.Cmd {
+	var id, description string
+	switch m.currentPage {
+	case nomad.JobsPage:
+		selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow()
+		if err != nil {
+			return nil
+		}
+		id, _ = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+		description = "job id"
+	case nomad.AllocationsPage:
+		selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow()
+		if err != nil {
+			return nil
+		}
+		allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+		if err != nil {
+			// e.g. a collapsed task group
+			return nil
+		}
+		id, description = allocInfo.Alloc.ID, "allocation id"
+	case nomad.NodeSpecPage:
+		id, description = m.nodeID, "node id"
+	default:
+		return nil
+	}
+	if short {
+		id, description = formatter.ShortAllocID(id), "short "+description
+	}
+	return m.copyCmd(id, fmt.Sprintf("%s %s", description, id))
+}
+
+// copyLogPath copies the path of the task's current log file on its client node, for debugging on the host
+func (m Model) copyLogPath(allocID, taskName string) tea.Cmd {
+	logPath := nomad.TaskLogPath(m.config.NomadDataDir, allocID, taskName, m.logType)
+	return m.copyCmd(logPath, logPath)
+}
+
+// recordError keeps err for the errors page, dropping the oldest beyond constants.ErrorHistoryCount
+func (m *Model) recordError(err error) {
+	dev.Error(fmt.Sprintf("%s: %v", m.currentPage, err))
+	m.errorHistory = append(m.errorHistory, nomad.ErrorRecord{Time: time.Now(), Page: m.currentPage, Err: err.Error()})
+	if len(m.errorHistory) > constants.ErrorHistoryCount {
+		m.errorHistory = m.errorHistory[len(m.errorHistory)-constants.ErrorHistoryCount:]
+	}
+}
+
+// copyEvent copies an event's complete JSON, as received before the jq query is applied
+func (m Model) copyEvent(event string) tea.Cmd {
+	return m.copyCmd(event, "event json")
+}
+
+// copyAllEvents copies the events shown as the jq query rendered them, one per line, newest last. Only the newest that
+// fit in EventsCopyMaxBytes are copied.
+func (m Model) copyAllEvents() tea.Cmd {
+	rows := m.getCurrentPageModel().FilteredPageRows()
+	if len(rows) == 0 {
+		return toastCmd(message.ToastMsg{Message: "No events to copy"})
+	}
+	var lines []string
+	size := 0
+	for i := len(rows) - 1; i >= 0; i-- {
+		size += len(rows[i].Row) + 1
+		if size > constants.EventsCopyMaxBytes {
+			break
+		}
+		lines = append(lines, rows[i].Row)
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	description := fmt.Sprintf("%d events", len(lines))
+	if len(lines) < len(rows) {
+		description = fmt.Sprintf("newest %d of %d events, capped at %d KB", len(lines), len(rows), constants.EventsCopyMaxBytes/1024)
+	}
+	return m.copyCmd(strings.Join(lines, "\n"), description)
+}
+
+// ExitErr is why the app exited on its own, if it did
+func (m Model) ExitErr() error {
+	return m.exitErr
+}
+
+func (m *Model) setDisconnected() {
+	if m.disconnectedSince.IsZero() {
+		m.disconnectedSince = time.Now()
+	}
+}
+
 func (m *Model) cleanupCmd() tea.Cmd {
 	return func() tea.Msg {
 		if m.execWebSocket != nil {
 			nomad.CloseWebSocket(m.execWebSocket)()
 		}
+		if m.eventsOutFile != nil {
+			_ = m.eventsOutFile.Close()
+		}
 		return message.CleanupCompleteMsg{}
 	}
 }
@@ -313,6 +1095,21 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 		}
 	}
 
+	// a collapsed task group's row expands it, and has no allocation for the allocation actions to act on
+	if m.currentPage == nomad.AllocationsPage && !m.currentPageFilterFocused() && !m.currentPageViewportSaving() {
+		if selectedPageRow, err := currentPageModel.GetSelectedPageRow(); err == nil {
+			if taskGroup, isCollapsed := nomad.CollapsedTaskGroupFromKey(selectedPageRow.Key); isCollapsed {
+				if key.Matches(msg, keymap.KeyMap.Forward) || key.Matches(msg, keymap.KeyMap.Collapse) {
+					delete(m.collapsedTaskGroups, taskGroup)
+					return m.getCurrentPageCmd()
+				}
+				if m.isAllocationAction(msg) {
+					return nil
+				}
+			}
+		}
+	}
+
 	if !m.currentPageFilterFocused() && !m.currentPageViewportSaving() {
 		switch {
 		case key.Matches(msg, keymap.KeyMap.Forward):
@@ -320,7 +1117,8 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 				switch m.currentPage {
 				case nomad.JobsPage:
 					m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
-				case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+					m.collapsedTaskGroups = nil
+				case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage, nomad.TaskEventsPage:
 					m.event = selectedPageRow.Key
 				case nomad.AllocationsPage:
 					allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
@@ -329,8 +1127,19 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 						return nil
 					}
 					m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+					m.logOffset = m.config.LogOffset
+					m.logsAllTasks = false
 				case nomad.LogsPage:
 					m.logline = selectedPageRow.Row
+					if selectedPageRow.Full != "" {
+						m.logline = selectedPageRow.Full
+					}
+				case nomad.SearchPage:
+					return m.goToSearchResult(selectedPageRow.Key)
+				case nomad.ACLPoliciesPage:
+					m.aclPolicyName = selectedPageRow.Key
+				case nomad.JobEvaluationsPage:
+					return m.followEvaluation(selectedPageRow.Key)
 				}
 
 				nextPage := m.currentPage.Forward()
@@ -351,6 +1160,9 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 				}
 
 				backPage := m.currentPage.Backward()
+				if returnsToOpener(m.currentPage) {
+					backPage = m.popReturnPage()
+				}
 				if backPage != m.currentPage {
 					m.setPage(backPage)
 					cmds = append(cmds, m.getCurrentPageCmd())
@@ -360,6 +1172,8 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 
 		case key.Matches(msg, keymap.KeyMap.Reload):
 			if m.currentPage.DoesReload() {
+				// drop the pending update so the next one is a full interval after this reload rather than right after it
+				m.updateID = nextUpdateID()
 				m.getCurrentPageModel().SetLoading(true)
 				return m.getCurrentPageCmd()
 			}
@@ -387,6 +1201,7 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 				switch m.currentPage {
 				case nomad.JobsPage:
 					m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+					m.jobSpecSource = false
 					m.setPage(nomad.JobSpecPage)
 					return m.getCurrentPageCmd()
 				case nomad.AllocationsPage:
@@ -402,30 +1217,428 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 			}
 		}
 
-		if key.Matches(msg, keymap.KeyMap.JobEvents) && m.currentPage == nomad.JobsPage {
-			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
-				m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
-				m.setPage(nomad.JobEventsPage)
-				return m.getCurrentPageCmd()
+		if key.Matches(msg, keymap.KeyMap.Edit) && !m.config.ReadOnly {
+			switch m.currentPage {
+			case nomad.JobsPage:
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+					return nomad.PrepareJobSpecForEdit(m.client, m.jobID, m.jobNamespace)
+				}
+			case nomad.JobSpecPage:
+				return nomad.PrepareJobSpecForEdit(m.client, m.jobID, m.jobNamespace)
 			}
 		}
 
-		if key.Matches(msg, keymap.KeyMap.AllocEvents) && m.currentPage == nomad.AllocationsPage {
+		if key.Matches(msg, keymap.KeyMap.Dispatch) && m.currentPage == nomad.JobsPage && !m.config.ReadOnly {
 			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
-				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
-				if err != nil {
-					m.err = err
-					return nil
-				}
-				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
-				m.setPage(nomad.AllocEventsPage)
-				return m.getCurrentPageCmd()
+				m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+				return nomad.FetchParameterizedJob(m.client, m.jobID, m.jobNamespace)
 			}
 		}
 
-		if key.Matches(msg, keymap.KeyMap.AllEvents) && m.currentPage == nomad.JobsPage {
-			m.setPage(nomad.AllEventsPage)
-			return m.getCurrentPageCmd()
+		if key.Matches(msg, keymap.KeyMap.CopyEvent) {
+			switch m.currentPage {
+			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					return m.copyEvent(selectedPageRow.Key)
+				}
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyEvents) {
+			switch m.currentPage {
+			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+				return m.copyAllEvents()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyLogPath) {
+			switch m.currentPage {
+			case nomad.AllocationsPage:
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+					if err != nil {
+						m.err = err
+						return nil
+					}
+					return m.copyLogPath(allocInfo.Alloc.ID, allocInfo.TaskName)
+				}
+			case nomad.LogsPage:
+				return m.copyLogPath(m.alloc.ID, m.taskName)
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Export) && m.currentPage == nomad.AllocationsPage {
+			return nomad.ExportAllocations(m.client, m.jobID, m.jobNamespace, m.config.ExportFormat)
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Reevaluate) && !m.config.ReadOnly {
+			switch m.currentPage {
+			case nomad.JobsPage:
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+					m.confirm = confirm.New(fmt.Sprintf("Force a new evaluation of %s?", m.jobID), nomad.ForceEvaluateJob(m.client, m.jobID, m.jobNamespace), m.width)
+					return nil
+				}
+			case nomad.AllocationsPage:
+				m.confirm = confirm.New(fmt.Sprintf("Force a new evaluation of %s?", m.jobID), nomad.ForceEvaluateJob(m.client, m.jobID, m.jobNamespace), m.width)
+				return nil
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.RestartAll) && m.currentPage == nomad.JobsPage && !m.config.ReadOnly {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				jobID, jobNamespace := nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+				prompt := fmt.Sprintf("Restart all running allocations of %s one at a time, %s apart?", jobID, constants.RollingRestartDelay)
+				m.confirm = confirm.NewTyped(prompt, jobID, nomad.StartRollingRestart(m.client, jobID, jobNamespace), m.width)
+				return nil
+			}
+		}
+
+		if m.currentPage == nomad.AllocationsPage {
+			switch {
+			case key.Matches(msg, keymap.KeyMap.Collapse):
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					if m.collapsedTaskGroups == nil {
+						m.collapsedTaskGroups = make(map[string]bool)
+					}
+					m.collapsedTaskGroups[selectedPageRow.Fields["task_group"]] = true
+					return m.getCurrentPageCmd()
+				}
+			case key.Matches(msg, keymap.KeyMap.CollapseAll):
+				if len(m.collapsedTaskGroups) > 0 {
+					m.collapsedTaskGroups = nil
+				} else {
+					m.collapsedTaskGroups = make(map[string]bool)
+					for _, r := range m.getCurrentPageModel().AllPageRows() {
+						m.collapsedTaskGroups[r.Fields["task_group"]] = true
+					}
+				}
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.StopAlloc) && m.currentPage == nomad.AllocationsPage && !m.config.ReadOnly {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				prompt := fmt.Sprintf("Stop allocation %s (%s)? Nomad will reschedule it.", formatter.ShortAllocID(allocInfo.Alloc.ID), allocInfo.Alloc.Name)
+				m.confirm = m.newDestructiveConfirm(prompt, formatter.ShortAllocID(allocInfo.Alloc.ID), nomad.StopAllocation(m.client, allocInfo.Alloc, m.jobNamespace))
+				return nil
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.JobEvents) && m.currentPage == nomad.JobsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(selectedPageRow.Key)
+				m.setPage(nomad.JobEventsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.AllocEvents) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.AllocEventsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.TaskEvents) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.TaskEventsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.ReverseOrder) && m.currentPage == nomad.TaskEventsPage && !m.currentPageLoading() {
+			m.taskEventsOldestFirst = !m.taskEventsOldestFirst
+			m.getCurrentPageModel().SetLoading(true)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.AllEvents) && m.currentPage == nomad.JobsPage {
+			m.setPage(nomad.AllEventsPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.ACLPolicies) && m.currentPage == nomad.JobsPage && m.aclReadable {
+			m.setPage(nomad.ACLPoliciesPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if (key.Matches(msg, keymap.KeyMap.NextNS) || key.Matches(msg, keymap.KeyMap.PrevNS)) && m.currentPage == nomad.JobsPage {
+			step := 1
+			if key.Matches(msg, keymap.KeyMap.PrevNS) {
+				step = -1
+			}
+			return nomad.CycleNamespace(m.client, m.config.Namespace, step)
+		}
+
+		if key.Matches(msg, keymap.KeyMap.HideDead) && m.currentPage == nomad.JobsPage && !m.currentPageLoading() {
+			m.hideDeadJobs = !m.hideDeadJobs
+			m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+			m.getCurrentPageModel().SetLoading(true)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.JobType) && m.currentPage == nomad.JobsPage && !m.currentPageLoading() {
+			m.jobType = nomad.NextJobType(m.jobType)
+			m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+			m.getCurrentPageModel().SetLoading(true)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.JobSource) && m.currentPage == nomad.JobSpecPage && !m.currentPageLoading() {
+			m.jobSpecSource = !m.jobSpecSource
+			m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+			m.getCurrentPageModel().SetLoading(true)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Placement) && m.currentPage == nomad.JobSpecPage {
+			m.setPage(nomad.JobPlacementPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.AllTaskLogs) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.logOffset = m.config.LogOffset
+				m.logsAllTasks, m.logsMerged = true, false
+				m.setPage(nomad.LogsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Ports) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.AllocPortsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Volumes) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.AllocVolumesPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Stats) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.AllocStatsPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Checks) && m.currentPage == nomad.AllocationsPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				allocInfo, err := nomad.AllocationInfoFromKey(selectedPageRow.Key)
+				if err != nil {
+					m.err = err
+					return nil
+				}
+				m.alloc, m.taskName = allocInfo.Alloc, allocInfo.TaskName
+				m.setPage(nomad.AllocChecksPage)
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Rollout) && m.currentPage == nomad.AllocationsPage {
+			m.rolloutHealth, m.rolloutHealthyNotified = nil, false
+			m.setPage(nomad.JobRolloutPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Deployment) && m.currentPage == nomad.AllocationsPage {
+			m.setPage(nomad.JobDeploymentPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Evaluations) && m.currentPage == nomad.AllocationsPage {
+			m.setPage(nomad.JobEvaluationsPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Coverage) && m.currentPage == nomad.AllocationsPage {
+			m.setPage(nomad.JobCoveragePage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Errors) && m.currentPage != nomad.ErrorsPage && !m.inPty {
+			m.pushReturnPage()
+			m.setPage(nomad.ErrorsPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.RawResponses) && m.config.CaptureResponses && m.currentPage != nomad.RawResponsesPage && !m.inPty {
+			m.pushReturnPage()
+			m.setPage(nomad.RawResponsesPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.About) && m.currentPage != nomad.AboutPage && !m.inPty {
+			m.pushReturnPage()
+			m.setPage(nomad.AboutPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyID) {
+			switch m.currentPage {
+			case nomad.JobsPage, nomad.AllocationsPage, nomad.NodeSpecPage:
+				return m.copyID(false)
+			}
+		}
+
+		// job IDs are names rather than UUIDs, so have no short form
+		if key.Matches(msg, keymap.KeyMap.CopyShortID) {
+			switch m.currentPage {
+			case nomad.AllocationsPage, nomad.NodeSpecPage:
+				return m.copyID(true)
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyValue) && m.currentPage == nomad.AboutPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				return m.copyCmd(selectedPageRow.Key, "value")
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyBody) && m.currentPage == nomad.RawResponsesPage {
+			if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+				return m.copyCmd(selectedPageRow.Key, "response body")
+			}
+		}
+
+		if m.currentPage == nomad.ErrorsPage {
+			switch {
+			case key.Matches(msg, keymap.KeyMap.CopyError):
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					return m.copyCmd(selectedPageRow.Key, "error")
+				}
+			case key.Matches(msg, keymap.KeyMap.ClearErrors):
+				m.errorHistory = nil
+				return m.getCurrentPageCmd()
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Top) && m.currentPage == nomad.JobsPage {
+			m.setPage(nomad.TopPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Servers) && m.currentPage == nomad.JobsPage {
+			m.setPage(nomad.ServersPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Quotas) && m.currentPage == nomad.JobsPage && m.quotasAvailable {
+			m.setPage(nomad.QuotasPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.NextJQQuery) {
+			switch m.currentPage {
+			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+				m.eventJQQueryIdx = (m.eventJQQueryIdx + 1) % len(m.getEventJQQueries())
+				m.reapplyEventJQQuery()
+				m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+				return nil
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.RegexFilter) {
+			switch m.currentPage {
+			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+				m.getCurrentPageModel().SetFilterRegex(!m.getCurrentPageModel().FilterRegex())
+				m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+				return nil
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.PauseEvents) {
+			switch m.currentPage {
+			case nomad.JobEventsPage, nomad.AllocEventsPage, nomad.AllEventsPage:
+				m.eventsPaused = !m.eventsPaused
+				if !m.eventsPaused && len(m.eventsBuffer) > 0 {
+					m.appendStreamedRows(m.eventsBuffer)
+					m.eventsBuffer, m.eventsDropped = nil, 0
+				}
+				m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+				return nil
+			}
+		}
+
+		if key.Matches(msg, keymap.KeyMap.Search) && m.currentPage == nomad.JobsPage {
+			m.setPage(nomad.SearchPage)
+			return m.getCurrentPageCmd()
+		}
+
+		if key.Matches(msg, keymap.KeyMap.HTMLSnapshot) {
+			return saveHTMLSnapshot(m.View())
+		}
+
+		if key.Matches(msg, keymap.KeyMap.CopyLink) && nomad.IsLinkable(m.currentPage) {
+			return m.copyCmd(m.currentLink().String(), "link")
+		}
+
+		for _, h := range m.footerHints {
+			if key.Matches(msg, h.binding) {
+				if cmd := runFooterHint(h.hint); cmd != nil {
+					return cmd
+				}
+			}
+		}
+
+		for _, a := range m.customActions {
+			if key.Matches(msg, a.binding) && a.availableOn(m.currentPage) {
+				if selectedPageRow, err := m.getCurrentPageModel().GetSelectedPageRow(); err == nil {
+					data, err := newCustomActionData(m.currentPage, selectedPageRow, m.config)
+					if err != nil {
+						m.err = err
+						return nil
+					}
+					return runCustomAction(a, data, m.config)
+				}
+			}
 		}
 
 		if m.currentPage == nomad.LogsPage {
@@ -446,6 +1659,48 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 					m.getCurrentPageModel().SetLoading(true)
 					return m.getCurrentPageCmd()
 				}
+
+			case key.Matches(msg, keymap.KeyMap.OpenInPager):
+				if !m.currentPageLoading() {
+					return writeToPagerFile(m.getCurrentPageModel().FilteredContent())
+				}
+
+			case key.Matches(msg, keymap.KeyMap.FromStart):
+				if !m.currentPageLoading() {
+					m.logsFromStart = !m.logsFromStart
+					m.getCurrentPageModel().SetLoading(true)
+					return m.getCurrentPageCmd()
+				}
+
+			case key.Matches(msg, keymap.KeyMap.OlderLogs):
+				if !m.currentPageLoading() && !m.logsFromStart {
+					m.logOffset += m.config.LogOffset
+					m.getCurrentPageModel().SetLoading(true)
+					return m.getCurrentPageCmd()
+				}
+
+			case key.Matches(msg, keymap.KeyMap.PrettyJSON):
+				if !m.currentPageLoading() {
+					m.logsPrettyJSON = !m.logsPrettyJSON
+					m.getCurrentPageModel().SetLoading(true)
+					return m.getCurrentPageCmd()
+				}
+
+			case key.Matches(msg, keymap.KeyMap.AllTaskLogs):
+				if !m.currentPageLoading() {
+					m.logsAllTasks, m.logsMerged = !m.logsAllTasks, false
+					m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+					m.getCurrentPageModel().SetLoading(true)
+					return m.getCurrentPageCmd()
+				}
+
+			case key.Matches(msg, keymap.KeyMap.MergeLogs):
+				if !m.currentPageLoading() {
+					m.logsMerged, m.logsAllTasks = !m.logsMerged, false
+					m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+					m.getCurrentPageModel().SetLoading(true)
+					return m.getCurrentPageCmd()
+				}
 			}
 		}
 	}
@@ -453,9 +1708,41 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+func (m *Model) goToSearchResult(key string) tea.Cmd {
+	resultType, resultKey, err := nomad.SearchResultFromKey(key)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	switch resultType {
+	case nomad.JobSearchResult:
+		m.jobID, m.jobNamespace = nomad.JobIDAndNamespaceFromKey(resultKey)
+		m.setPage(nomad.AllocationsPage)
+	case nomad.AllocSearchResult:
+		var alloc api.Allocation
+		if err = json.Unmarshal([]byte(resultKey), &alloc); err != nil {
+			m.err = err
+			return nil
+		}
+		m.alloc, m.taskName = alloc, nomad.DefaultTaskName(alloc, m.config.DefaultLogTasks)
+		m.jobID, m.jobNamespace = alloc.JobID, alloc.Namespace
+		m.setPage(nomad.AllocSpecPage)
+	case nomad.NodeSearchResult:
+		m.nodeID = resultKey
+		m.setPage(nomad.NodeSpecPage)
+	default:
+		return nil
+	}
+	return m.getCurrentPageCmd()
+}
+
 func (m *Model) setPage(page nomad.Page) {
 	m.getCurrentPageModel().HideToast()
 	m.currentPage = page
+	m.eventsPaused, m.eventsBuffer, m.eventsDropped = false, nil, 0
+	m.stopFollowingLogs()
+	m.header.SetBorderColor(m.getActiveNamespaceColor())
 	m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(page))
 	if page.DoesLoad() {
 		m.getCurrentPageModel().SetLoading(true)
@@ -468,6 +1755,108 @@ func (m *Model) getCurrentPageModel() *page.Model {
 	return m.pageModels[m.currentPage]
 }
 
+// getEventJQQueries is the configured event jq query followed by any named alternatives
+func (m Model) getEventJQQueries() []NamedJQQuery {
+	return append([]NamedJQQuery{{Name: "default", Code: m.config.Event.JQQuery}}, m.config.Event.JQQueries...)
+}
+
+// reapplyEventJQQuery re-renders events already shown, and any buffered, with the active jq query
+func (m *Model) reapplyEventJQQuery() {
+	code := m.getEventJQQueries()[m.eventJQQueryIdx].Code
+	reapply := func(rows []page.Row) []page.Row {
+		var updated []page.Row
+		for _, r := range rows {
+			if jq, err := nomad.RunJQQueryOnEvent(r.Key, code); err == nil {
+				r.Row = jq
+			}
+			updated = append(updated, r)
+		}
+		return updated
+	}
+	m.getCurrentPageModel().SetAllPageData(reapply(m.getCurrentPageModel().AllPageRows()))
+	m.eventsBuffer = reapply(m.eventsBuffer)
+}
+
+// loadMoreJobs fetches the next page of jobs when the selection reaches the bottom of those loaded, or every page once
+// when a filter is entered, as it can only match loaded jobs. Clearing the filter returns to the pages loaded by
+// scrolling.
+func (m *Model) loadMoreJobs(filterChanged bool) tea.Cmd {
+	filtering := filterChanged && m.getCurrentPageModel().FilterValue() != ""
+	if filterChanged && !filtering && m.jobsAllFetched {
+		m.jobsAllFetched = false
+		m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+		return m.fetchJobs(m.jobsPages)
+	}
+	if !m.jobsMoreAvailable || m.jobsLoadingMore || m.currentPageLoading() {
+		return nil
+	}
+	if !filtering && !m.getCurrentPageModel().ViewportSelectionAtBottom() {
+		return nil
+	}
+	m.jobsLoadingMore = true
+	if filtering {
+		m.jobsAllFetched = true
+		m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+		return m.fetchJobs(0)
+	}
+	m.jobsPages += 1
+	m.getCurrentPageModel().SetFilterPrefix(m.getFilterPrefix(m.currentPage))
+	return m.fetchJobs(m.jobsPages)
+}
+
+// fetchJobs fetches the first pages of jobs, or every page if pages is 0. A configured sort needs every job to order
+// them, so fetches every page too.
+func (m *Model) fetchJobs(pages int) tea.Cmd {
+	perPage := m.config.JobsPerPage
+	if len(m.config.Sort[nomad.JobsPage]) > 0 {
+		perPage = 0
+	}
+	m.jobsFetchID = nextUpdateID()
+	return nomad.FetchJobs(m.client, nomad.FilterUsesMeta(m.getCurrentPageModel().FilterValue()), m.config.ShowDatacenters, m.hideDeadJobs, m.jobType, perPage, pages, m.config.Icons, m.jobsFetchID)
+}
+
+// followLogs streams the logs written after they loaded, which is only done for a single task as lines from many
+// can't be interleaved by time as they arrive
+func (m *Model) followLogs() tea.Cmd {
+	m.stopFollowingLogs()
+	if m.logsMerged || m.logsAllTasks {
+		return nil
+	}
+	m.logsStream = nomad.LogsStream{ID: nextUpdateID()}
+	return nomad.FollowLogs(m.client, m.alloc, m.taskName, m.logType, m.logsStream.ID)
+}
+
+func (m *Model) stopFollowingLogs() {
+	m.logsStream.Close()
+	m.logsStream, m.logsPartial = nomad.LogsStream{}, ""
+}
+
+func (m Model) readLogsStream() tea.Cmd {
+	return nomad.ReadLogsStream(m.logsStream, m.logsPartial, m.config.LogCoalesce, m.config.LogRedactions, m.logsPrettyJSON, m.config.LogMaxLineLength)
+}
+
+func (m Model) readEventsStream() tea.Cmd {
+	return nomad.ReadEventsStreamNextMessage(m.eventsStream, m.getEventJQQueries()[m.eventJQQueryIdx].Code)
+}
+
+// resubscribeToEvents reloads the events page after its stream failed, if refreshing the token fixes it
+func (m *Model) resubscribeToEvents(streamErr error) tea.Cmd {
+	if err := m.refreshToken(streamErr); err != nil {
+		m.err = err
+		return nil
+	}
+	return tea.Batch(toastCmd(message.ToastMsg{Message: "Token refreshed, resubscribed to events"}), m.getCurrentPageCmd())
+}
+
+// appendStreamedRows appends rows to the current page, keeping the newest in view if the bottom was selected
+func (m *Model) appendStreamedRows(rows []page.Row) {
+	scrollDown := m.getCurrentPageModel().ViewportSelectionAtBottom()
+	m.getCurrentPageModel().AppendToViewport(rows, true)
+	if scrollDown {
+		m.getCurrentPageModel().ScrollViewportToBottom()
+	}
+}
+
 func (m *Model) appendToViewport(content string, startOnNewLine bool) {
 	stringRows := strings.Split(content, "\n")
 	var pageRows []page.Row
@@ -508,15 +1897,43 @@ func (m *Model) setInPty(inPty bool) {
 }
 
 func (m *Model) updateKeyHelp() {
-	m.header.KeyHelp = nomad.GetPageKeyHelp(m.currentPage, m.currentPageFilterFocused(), m.currentPageFilterApplied(), m.currentPageViewportSaving(), m.getCurrentPageModel().EnteringInput(), m.inPty, m.webSocketConnected, m.logType)
+	m.header.KeyHelp = nomad.GetPageKeyHelp(m.currentPage, nomad.KeyHelpState{
+		FilterFocused:          m.currentPageFilterFocused(),
+		FilterApplied:          m.currentPageFilterApplied(),
+		Saving:                 m.currentPageViewportSaving(),
+		EnteringInput:          m.getCurrentPageModel().EnteringInput(),
+		InPty:                  m.inPty,
+		WebSocketConnected:     m.webSocketConnected,
+		ReadOnly:               m.config.ReadOnly,
+		ACLReadable:            m.aclReadable,
+		QuotasAvailable:        m.quotasAvailable,
+		LogsMerged:             m.logsMerged,
+		LogsAllTasks:           m.logsAllTasks,
+		LogsPrettyJSON:         m.logsPrettyJSON,
+		LogsFromStart:          m.logsFromStart,
+		EventsPaused:           m.eventsPaused,
+		MultipleEventJQQueries: len(m.config.Event.JQQueries) > 0,
+		EventsFilterRegex:      m.getCurrentPageModel().FilterRegex(),
+		DeadJobsHidden:         m.hideDeadJobs,
+		TaskGroupsCollapsed:    len(m.collapsedTaskGroups) > 0,
+		CapturingResponses:     m.config.CaptureResponses,
+		LogType:                m.logType,
+		FooterHints:            append(footerHintKeyBindings(m.footerHints), customActionKeyBindings(m.customActions, m.currentPage)...),
+	})
 }
 
-func (m Model) getCurrentPageCmd() tea.Cmd {
+func (m *Model) getCurrentPageCmd() tea.Cmd {
+	if note, unsupported := nomad.UnsupportedPageNote(m.currentPage, m.serverVersion); unsupported {
+		return nomad.LoadUnsupportedPage(m.currentPage, note)
+	}
 	switch m.currentPage {
 	case nomad.JobsPage:
-		return nomad.FetchJobs(m.client)
+		if m.jobsAllFetched {
+			return m.fetchJobs(0)
+		}
+		return m.fetchJobs(m.jobsPages)
 	case nomad.JobSpecPage:
-		return nomad.FetchJobSpec(m.client, m.jobID, m.jobNamespace)
+		return nomad.FetchJobSpec(m.client, m.jobID, m.jobNamespace, m.jobSpecSource)
 	case nomad.JobEventsPage:
 		return nomad.FetchEventsStream(m.client, nomad.TopicsForJob(m.config.Event.Topics, m.jobID), m.jobNamespace, nomad.JobEventsPage)
 	case nomad.JobEventPage:
@@ -530,24 +1947,178 @@ func (m Model) getCurrentPageCmd() tea.Cmd {
 	case nomad.AllEventPage:
 		return nomad.PrettifyLine(m.event, nomad.AllEventPage)
 	case nomad.AllocationsPage:
-		return nomad.FetchAllocations(m.client, m.jobID, m.jobNamespace)
+		return nomad.FetchAllocations(m.client, m.jobID, m.jobNamespace, m.config.ShowDatacenters, m.config.Icons, m.collapsedTaskGroups)
 	case nomad.ExecPage:
 		return nomad.LoadExecPage()
 	case nomad.AllocSpecPage:
 		return nomad.FetchAllocSpec(m.client, m.alloc.ID)
 	case nomad.LogsPage:
-		return nomad.FetchLogs(m.client, m.alloc, m.taskName, m.logType, m.config.LogOffset)
+		if m.logsAllTasks {
+			return nomad.FetchAllTaskLogs(m.client, m.alloc, m.logType, m.logOffset, m.logsFromStart, m.config.LogRedactions, m.logsPrettyJSON, m.config.LogMaxLineLength)
+		}
+		if m.logsMerged {
+			return nomad.FetchMergedLogs(m.client, m.alloc, m.taskName, m.logType, m.logOffset, m.logsFromStart, m.config.LogRedactions, m.logsPrettyJSON, m.config.LogMaxLineLength)
+		}
+		return nomad.FetchLogs(m.client, m.alloc, m.taskName, m.logType, m.logOffset, m.logsFromStart, m.config.LogRedactions, m.logsPrettyJSON, m.config.LogMaxLineLength)
 	case nomad.LoglinePage:
-		return nomad.PrettifyLine(m.logline, nomad.LoglinePage)
+		return nomad.FetchLogline(m.logline, m.config.LogMaxLineLength)
+	case nomad.SearchPage:
+		return nomad.FetchSearchResults(m.client, m.getCurrentPageModel().FilterValue())
+	case nomad.NodeSpecPage:
+		return nomad.FetchNodeSpec(m.client, m.nodeID)
+	case nomad.DispatchPage:
+		return nomad.LoadDispatchPage()
+	case nomad.EvaluationPage:
+		return nomad.LoadEvaluationPage(m.evalID)
+	case nomad.ACLPoliciesPage:
+		return nomad.FetchACLPolicies(m.client)
+	case nomad.ACLPolicyPage:
+		return nomad.FetchACLPolicy(m.client, m.aclPolicyName)
+	case nomad.TaskEventsPage:
+		return nomad.FetchTaskEvents(m.client, m.alloc.ID, m.taskName, m.taskEventsOldestFirst)
+	case nomad.TaskEventPage:
+		return nomad.PrettifyLine(m.event, nomad.TaskEventPage)
+	case nomad.TopPage:
+		return nomad.FetchTop(m.client)
+	case nomad.ServersPage:
+		return nomad.FetchServers(m.client)
+	case nomad.QuotasPage:
+		return nomad.FetchQuotas(m.client)
+	case nomad.JobPlacementPage:
+		return nomad.FetchJobPlacement(m.client, m.jobID, m.jobNamespace)
+	case nomad.JobCoveragePage:
+		return nomad.FetchJobCoverage(m.client, m.jobID, m.jobNamespace)
+	case nomad.AllocPortsPage:
+		return nomad.FetchAllocPorts(m.client, m.alloc.ID)
+	case nomad.AllocVolumesPage:
+		return nomad.FetchAllocVolumes(m.client, m.alloc.ID)
+	case nomad.AllocStatsPage:
+		return nomad.FetchAllocStats(m.client, m.alloc.ID)
+	case nomad.AllocChecksPage:
+		return nomad.FetchAllocChecks(m.client, m.alloc.ID)
+	case nomad.JobEvaluationsPage:
+		return nomad.FetchJobEvaluations(m.client, m.jobID, m.jobNamespace)
+	case nomad.JobDeploymentPage:
+		return nomad.FetchJobDeployment(m.client, m.jobID, m.jobNamespace)
+	case nomad.ErrorsPage:
+		return nomad.ListErrors(m.errorHistory)
+	case nomad.RawResponsesPage:
+		return nomad.ListRawResponses(m.config.responses.list(), m.config.CaptureResponses)
+	case nomad.AboutPage:
+		return nomad.FetchAbout(m.client, nomad.AboutInfo{
+			Version:       m.config.Version,
+			Commit:        m.config.SHA,
+			Addr:          m.config.URL,
+			ServerVersion: m.serverVersion,
+			Namespace:     m.config.Namespace,
+			Region:        m.config.Region,
+			Profile:       m.config.ProfileName,
+		})
+	case nomad.JobRolloutPage:
+		return nomad.FetchJobRollout(m.client, m.jobID, m.jobNamespace)
 	default:
 		panic("page load command not found")
 	}
 }
 
+// isAllocationAction is true if msg is a key for an action on the selected allocation on the allocations page
+func (m Model) isAllocationAction(msg tea.KeyMsg) bool {
+	for _, b := range []key.Binding{
+		keymap.KeyMap.Exec, keymap.KeyMap.Spec, keymap.KeyMap.CopyID, keymap.KeyMap.CopyShortID, keymap.KeyMap.CopyLogPath,
+		keymap.KeyMap.StopAlloc, keymap.KeyMap.AllocEvents, keymap.KeyMap.TaskEvents, keymap.KeyMap.AllTaskLogs,
+		keymap.KeyMap.Ports, keymap.KeyMap.Volumes, keymap.KeyMap.Stats, keymap.KeyMap.Checks,
+	} {
+		if key.Matches(msg, b) {
+			return true
+		}
+	}
+	for _, a := range m.customActions {
+		if key.Matches(msg, a.binding) && a.availableOn(m.currentPage) {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsToOpener is true for pages that can be opened from many others, so return to whichever they were opened from
+func returnsToOpener(p nomad.Page) bool {
+	switch p {
+	case nomad.EvaluationPage, nomad.ErrorsPage, nomad.RawResponsesPage, nomad.AboutPage:
+		return true
+	}
+	return false
+}
+
+// pushReturnPage records the current page to return to from a page that returns to its opener. Opening one from any
+// other kind of page starts over, as the recorded pages were left some other way.
+func (m *Model) pushReturnPage() {
+	if !returnsToOpener(m.currentPage) {
+		m.returnPages = nil
+	}
+	m.returnPages = append(m.returnPages, m.currentPage)
+}
+
+// peekReturnPage is the page to return to on back from a page that returns to its opener, defaulting to the jobs page
+func (m Model) peekReturnPage() nomad.Page {
+	if len(m.returnPages) == 0 {
+		return nomad.JobsPage
+	}
+	return m.returnPages[len(m.returnPages)-1]
+}
+
+// popReturnPage removes and returns the page to return to on back from a page that returns to its opener
+func (m *Model) popReturnPage() nomad.Page {
+	p := m.peekReturnPage()
+	if len(m.returnPages) > 0 {
+		m.returnPages = m.returnPages[:len(m.returnPages)-1]
+	}
+	return p
+}
+
+// evalPollErrorStatus stands in for the followed evaluation's status while it can't be fetched
+const evalPollErrorStatus = "poll error"
+
+// followEvaluation shows the evaluation's status until it completes, returning to the current page if it succeeds
+func (m *Model) followEvaluation(evalID string) tea.Cmd {
+	if evalID == "" {
+		return nil
+	}
+	if m.currentPage != nomad.EvaluationPage {
+		m.pushReturnPage()
+	}
+	m.evalID, m.evalStatus, m.evalPollID = evalID, "", nextUpdateID()
+	m.setPage(nomad.EvaluationPage)
+	return m.getCurrentPageCmd()
+}
+
+// requestDispatchInputAgain shows err above the dispatch input, keeping what was entered so it can be corrected
+func (m *Model) requestDispatchInputAgain(err error) {
+	m.getCurrentPageModel().SetInputPrefix(style.StdErr.Render("Error: "+err.Error()) + "\n" + nomad.DispatchInputPrefix(m.dispatchJob))
+	m.getCurrentPageModel().SetDoesNeedNewInput()
+}
+
+func (m Model) getActiveNamespaceColor() string {
+	namespace := m.config.Namespace
+	if m.currentPage.IsJobScoped() && m.jobNamespace != "" {
+		namespace = m.jobNamespace
+	}
+	return getNamespaceColor(m.config, namespace)
+}
+
 func (m Model) getPageHeight() int {
 	return m.height - m.header.ViewHeight()
 }
 
+// newDestructiveConfirm confirms with a keypress, or by typing name in namespaces configured to require it
+func (m Model) newDestructiveConfirm(prompt, name string, onConfirm tea.Cmd) confirm.Model {
+	for _, ns := range m.config.ConfirmTyped {
+		if ns == m.jobNamespace {
+			return confirm.NewTyped(prompt, name, onConfirm, m.width)
+		}
+	}
+	return confirm.New(prompt, onConfirm, m.width)
+}
+
 func (m Model) currentPageLoading() bool {
 	return m.getCurrentPageModel().Loading()
 }
@@ -565,7 +2136,42 @@ func (m Model) currentPageViewportSaving() bool {
 }
 
 func (m Model) getFilterPrefix(page nomad.Page) string {
-	return page.GetFilterPrefix(m.jobID, m.taskName, m.alloc.ID, m.config.Event.Topics, m.config.Event.Namespace)
+	if page == nomad.JobsPage {
+		prefix := nomad.JobsFilterPrefix(m.hideDeadJobs, m.jobType)
+		if m.jobsLoadingMore {
+			prefix += " (loading more...)"
+		} else if m.jobsAllFetched {
+			prefix += " (all pages, r to refresh)"
+		} else if m.jobsMoreAvailable {
+			prefix += " (more on scroll)"
+		}
+		return prefix
+	}
+	if page == nomad.JobSpecPage && m.jobSpecSource {
+		return nomad.JobSourceFilterPrefix(m.jobID)
+	}
+	if page == nomad.LogsPage && m.logsAllTasks {
+		return nomad.AllTaskLogsFilterPrefix(m.alloc.ID)
+	}
+	if page == nomad.LogsPage && m.logsMerged {
+		return nomad.MergedLogsFilterPrefix(m.taskName, m.alloc.TaskGroup)
+	}
+	prefix := page.GetFilterPrefix(m.jobID, m.taskName, m.alloc.ID, m.nodeID, m.evalID, m.aclPolicyName, m.config.Event.Topics, m.config.Event.Namespace)
+	if page == nomad.JobEventsPage || page == nomad.AllocEventsPage || page == nomad.AllEventsPage {
+		if len(m.config.Event.JQQueries) > 0 {
+			prefix = fmt.Sprintf("%s [query: %s]", prefix, m.getEventJQQueries()[m.eventJQQueryIdx].Name)
+		}
+		if m.eventsPaused {
+			prefix = fmt.Sprintf("%s (paused, %d buffered)", prefix, len(m.eventsBuffer))
+			if m.eventsDropped > 0 {
+				prefix = fmt.Sprintf("%s (%d oldest dropped)", prefix, m.eventsDropped)
+			}
+		}
+		if pm, ok := m.pageModels[page]; ok && pm.FilterRegex() {
+			prefix += " (regex filter)"
+		}
+	}
+	return prefix
 }
 
 func getVersionString(v, s string) string {
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// responseRecorder keeps the most recent api responses, oldest first, to show what Nomad actually returned
+type responseRecorder struct {
+	mu        sync.Mutex
+	responses []nomad.RawResponse
+}
+
+func (r *responseRecorder) record(response nomad.RawResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, response)
+	if len(r.responses) > constants.RawResponseCount {
+		r.responses = r.responses[len(r.responses)-constants.RawResponseCount:]
+	}
+}
+
+func (r *responseRecorder) list() []nomad.RawResponse {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]nomad.RawResponse{}, r.responses...)
+}
+
+// capturingTransport records each response's body as it is read by the Nomad client. It is only set after the Nomad
+// client is created, as the client requires an *http.Transport to configure TLS.
+type capturingTransport struct {
+	base     http.RoundTripper
+	recorder *responseRecorder
+}
+
+func (t capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &capturingBody{
+		ReadCloser: resp.Body,
+		recorder:   t.recorder,
+		response:   nomad.RawResponse{Time: time.Now(), Method: req.Method, URL: req.URL.RequestURI(), Status: resp.StatusCode},
+		gzipped:    resp.Header.Get("Content-Encoding") == "gzip",
+	}
+	return resp, nil
+}
+
+// capturingBody keeps up to constants.RawResponseMaxBytes of what's read, recording it on close. Streams, e.g. events,
+// are recorded when they end.
+type capturingBody struct {
+	io.ReadCloser
+	recorder *responseRecorder
+	response nomad.RawResponse
+	gzipped  bool
+	captured bytes.Buffer
+	closed   bool
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if remaining := constants.RawResponseMaxBytes - b.captured.Len(); remaining > 0 {
+		b.captured.Write(p[:min(n, remaining)])
+	}
+	if n > 0 && b.captured.Len() >= constants.RawResponseMaxBytes {
+		b.response.Truncated = true
+	}
+	return n, err
+}
+
+func (b *capturingBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		body := b.captured.Bytes()
+		if b.gzipped {
+			// a truncated body unzips as far as it goes
+			if r, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+				body, _ = io.ReadAll(io.LimitReader(r, constants.RawResponseMaxBytes))
+			}
+		}
+		// bodies are shown, copied and saved, so must not include the token, e.g. from /v1/acl/token/self
+		b.response.Body = dev.Redact(string(body))
+		b.response.URL = dev.Redact(b.response.URL)
+		b.recorder.record(b.response)
+	}
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"io"
+	"os"
+	"strings"
+)
+
+type ClipboardStrategy string
+
+const (
+	SystemClipboard ClipboardStrategy = "system"
+	// OSC52Clipboard writes an escape sequence asking the terminal to copy. Whether it did can't be known.
+	OSC52Clipboard ClipboardStrategy = "osc52"
+)
+
+var DefaultClipboardStrategies = []ClipboardStrategy{SystemClipboard, OSC52Clipboard}
+
+// copyCmd copies text to the clipboard, trying each configured strategy in order, and toasts the outcome. Every copy
+// action goes through it.
+func (m Model) copyCmd(text, description string) tea.Cmd {
+	strategies, sshOutput := m.config.ClipboardStrategies, m.config.SSHOutput
+	return func() tea.Msg {
+		used, err := copyToClipboard(text, strategies, sshOutput)
+		if err != nil {
+			return message.ToastMsg{Err: fmt.Errorf("could not copy %s: %w", description, err)}
+		}
+		if used == OSC52Clipboard {
+			return message.ToastMsg{Message: fmt.Sprintf("Sent %s to the terminal clipboard", description)}
+		}
+		return message.ToastMsg{Message: fmt.Sprintf("Copied %s", description)}
+	}
+}
+
+// copyToClipboard returns the first strategy that copied successfully. Over ssh, the system clipboard is the server's,
+// so it is skipped and sshOutput is used for OSC52.
+func copyToClipboard(text string, strategies []ClipboardStrategy, sshOutput io.Writer) (ClipboardStrategy, error) {
+	var failures []string
+	for _, s := range strategies {
+		var err error
+		switch s {
+		case SystemClipboard:
+			if sshOutput != nil {
+				continue
+			}
+			err = clipboard.WriteAll(text)
+		case OSC52Clipboard:
+			out := io.Writer(os.Stdout)
+			if sshOutput != nil {
+				out = sshOutput
+			}
+			err = writeOSC52(out, text)
+		default:
+			err = errors.New("unknown strategy")
+		}
+		if err == nil {
+			return s, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", s, err))
+	}
+	if len(failures) == 0 {
+		return "", errors.New("no clipboard strategy available")
+	}
+	return "", errors.New(strings.Join(failures, ", "))
+}
+
+func writeOSC52(out io.Writer, text string) error {
+	_, err := fmt.Fprintf(out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/components/viewport"
+	"github.com/robinovitch61/wander/internal/tui/keymap"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// CustomAction runs Command, a template filled from the selected row, handing it the terminal until it exits. It is
+// available on Pages, by name, or on the jobs and allocations pages if none are given.
+type CustomAction struct {
+	Key     string   `mapstructure:"key"`
+	Label   string   `mapstructure:"label"`
+	Command string   `mapstructure:"command"`
+	Pages   []string `mapstructure:"pages"`
+}
+
+// customActionData is what a custom action's command template can use. Every value is shell quoted before the command
+// is filled, as they come from the cluster and the command runs in a shell.
+type customActionData struct {
+	JobID, Namespace             string
+	AllocID, TaskGroup, TaskName string
+	NodeID                       string
+	Fields                       map[string]string
+	Addr                         string
+}
+
+var customActionPages = []nomad.Page{nomad.JobsPage, nomad.AllocationsPage}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoted returns d with every value shell quoted
+func (d customActionData) quoted() customActionData {
+	q := customActionData{
+		JobID:     shellQuote(d.JobID),
+		Namespace: shellQuote(d.Namespace),
+		AllocID:   shellQuote(d.AllocID),
+		TaskGroup: shellQuote(d.TaskGroup),
+		TaskName:  shellQuote(d.TaskName),
+		NodeID:    shellQuote(d.NodeID),
+		Fields:    make(map[string]string, len(d.Fields)),
+		Addr:      shellQuote(d.Addr),
+	}
+	for k, v := range d.Fields {
+		q.Fields[k] = shellQuote(v)
+	}
+	return q
+}
+
+// builtInKeys are the keys of wander's own bindings, which are matched before custom actions so can't be taken by them
+func builtInKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, keyMap := range []interface{}{keymap.KeyMap, viewport.GetKeyMap()} {
+		v := reflect.ValueOf(keyMap)
+		for i := 0; i < v.NumField(); i++ {
+			if binding, ok := v.Field(i).Interface().(key.Binding); ok {
+				for _, k := range binding.Keys() {
+					keys[k] = true
+				}
+			}
+		}
+	}
+	return keys
+}
+
+type customActionBinding struct {
+	action   CustomAction
+	template *template.Template
+	pages    []nomad.Page
+	binding  key.Binding
+}
+
+type customActionFinishedMsg struct {
+	label string
+	err   error
+}
+
+// ParseCustomAction validates a custom action, returning the pages it is available on
+func ParseCustomAction(a CustomAction) (*template.Template, []nomad.Page, error) {
+	if a.Key == "" || a.Label == "" || a.Command == "" {
+		return nil, nil, fmt.Errorf("each action requires a key, label and command")
+	}
+	if builtInKeys()[a.Key] {
+		return nil, nil, fmt.Errorf("action %s: key %s is already used by wander", a.Label, a.Key)
+	}
+	t, err := template.New(a.Label).Option("missingkey=zero").Parse(a.Command)
+	if err != nil {
+		return nil, nil, fmt.Errorf("action %s: %w", a.Label, err)
+	}
+	if len(a.Pages) == 0 {
+		return t, customActionPages, nil
+	}
+	var pages []nomad.Page
+	for _, name := range a.Pages {
+		var found bool
+		for _, p := range customActionPages {
+			if p.String() == name {
+				pages, found = append(pages, p), true
+			}
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("action %s: page %s must be one of jobs, allocations", a.Label, name)
+		}
+	}
+	return t, pages, nil
+}
+
+// getCustomActionBindings excludes every custom action in read only mode, as they may modify the cluster. Actions are
+// validated when the config is read.
+func getCustomActionBindings(actions []CustomAction, readOnly bool) []customActionBinding {
+	if readOnly {
+		return nil
+	}
+	var bindings []customActionBinding
+	for _, a := range actions {
+		t, pages, err := ParseCustomAction(a)
+		if err != nil {
+			continue
+		}
+		bindings = append(bindings, customActionBinding{
+			action:   a,
+			template: t,
+			pages:    pages,
+			binding: key.NewBinding(
+				key.WithKeys(a.Key),
+				// labelled so they aren't mistaken for wander's own actions
+				key.WithHelp(a.Key, "custom: "+a.Label),
+			),
+		})
+	}
+	return bindings
+}
+
+func customActionKeyBindings(actions []customActionBinding, currentPage nomad.Page) []key.Binding {
+	var bindings []key.Binding
+	for _, a := range actions {
+		if a.availableOn(currentPage) {
+			bindings = append(bindings, a.binding)
+		}
+	}
+	return bindings
+}
+
+func (a customActionBinding) availableOn(p nomad.Page) bool {
+	for _, page := range a.pages {
+		if page == p {
+			return true
+		}
+	}
+	return false
+}
+
+func newCustomActionData(currentPage nomad.Page, row page.Row, c Config) (customActionData, error) {
+	data := customActionData{Fields: row.Fields, Addr: c.URL}
+	switch currentPage {
+	case nomad.JobsPage:
+		data.JobID, data.Namespace = nomad.JobIDAndNamespaceFromKey(row.Key)
+	case nomad.AllocationsPage:
+		allocInfo, err := nomad.AllocationInfoFromKey(row.Key)
+		if err != nil {
+			return customActionData{}, err
+		}
+		alloc := allocInfo.Alloc
+		data.JobID, data.Namespace, data.AllocID, data.TaskGroup, data.NodeID = alloc.JobID, alloc.Namespace, alloc.ID, alloc.TaskGroup, alloc.NodeID
+		data.TaskName = allocInfo.TaskName
+	}
+	return data, nil
+}
+
+// runCustomAction runs the action's command in a shell with the nomad CLI's environment variables set, waiting for enter
+// after it exits so its output can be read before returning
+func runCustomAction(a customActionBinding, data customActionData, c Config) tea.Cmd {
+	var command bytes.Buffer
+	if err := a.template.Execute(&command, data.quoted()); err != nil {
+		return toastCmd(message.ToastMsg{Err: fmt.Errorf("%s: %w", a.action.Label, err)})
+	}
+	script := `sh -c "$1"; status=$?; printf '\nPress enter to return to wander'; read _; exit $status`
+	cmd := exec.Command("sh", "-c", script, "wander", command.String())
+	cmd.Env = append(os.Environ(), "NOMAD_ADDR="+c.URL, "NOMAD_TOKEN="+c.Token, "NOMAD_REGION="+c.Region, "NOMAD_NAMESPACE="+data.Namespace)
+	label := a.action.Label
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return customActionFinishedMsg{label: label, err: err}
+	})
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseCustomActionRejectsBuiltInKeys(t *testing.T) {
+	for _, k := range []string{"Y", "j", "ctrl+c", "enter"} {
+		if _, _, err := ParseCustomAction(CustomAction{Key: k, Label: "restart", Command: "true"}); err == nil {
+			t.Errorf("expected key %s to be rejected", k)
+		}
+	}
+	if _, _, err := ParseCustomAction(CustomAction{Key: "1", Label: "restart", Command: "true"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCustomActionDataQuoted(t *testing.T) {
+	a := CustomAction{Key: "1", Label: "restart", Command: "myctl restart {{.JobID}} -n {{.Namespace}} {{.Fields.status}}"}
+	template, _, err := ParseCustomAction(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := customActionData{JobID: "job; rm -rf ~", Namespace: "it's", Fields: map[string]string{"status": "$(id)"}}
+
+	var command bytes.Buffer
+	if err = template.Execute(&command, data.quoted()); err != nil {
+		t.Fatal(err)
+	}
+	expected := `myctl restart 'job; rm -rf ~' -n 'it'\''s' '$(id)'`
+	if command.String() != expected {
+		t.Errorf("expected %s, got %s", expected, command.String())
+	}
+}
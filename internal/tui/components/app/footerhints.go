@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// FooterHint is a custom key hint shown below the key help. If Command is set, the key runs it in a shell without
+// output to the terminal. If URL is set, the key opens it in the browser.
+type FooterHint struct {
+	Key     string `mapstructure:"key"`
+	Label   string `mapstructure:"label"`
+	Command string `mapstructure:"command"`
+	URL     string `mapstructure:"url"`
+}
+
+type footerHintBinding struct {
+	hint    FooterHint
+	binding key.Binding
+}
+
+// getFooterHintBindings excludes hints that run commands in read only mode, as they may modify the cluster
+func getFooterHintBindings(hints []FooterHint, readOnly bool) []footerHintBinding {
+	var bindings []footerHintBinding
+	for _, h := range hints {
+		if readOnly && h.Command != "" {
+			continue
+		}
+		bindings = append(bindings, footerHintBinding{
+			hint: h,
+			binding: key.NewBinding(
+				key.WithKeys(h.Key),
+				key.WithHelp(h.Key, h.Label),
+			),
+		})
+	}
+	return bindings
+}
+
+func footerHintKeyBindings(hints []footerHintBinding) []key.Binding {
+	var bindings []key.Binding
+	for _, h := range hints {
+		bindings = append(bindings, h.binding)
+	}
+	return bindings
+}
+
+func runFooterHint(h FooterHint) tea.Cmd {
+	switch {
+	case h.Command != "":
+		return func() tea.Msg {
+			output, err := exec.Command("sh", "-c", h.Command).CombinedOutput()
+			if err != nil {
+				return message.ToastMsg{Err: fmt.Errorf("%s: %v %s", h.Label, err, strings.TrimSpace(string(output)))}
+			}
+			return message.ToastMsg{Message: fmt.Sprintf("Ran %s", h.Label)}
+		}
+	case h.URL != "":
+		return func() tea.Msg {
+			if err := openURL(h.URL); err != nil {
+				return message.ToastMsg{Err: fmt.Errorf("%s: %w", h.Label, err)}
+			}
+			return message.ToastMsg{Message: fmt.Sprintf("Opened %s", h.URL)}
+		}
+	}
+	return nil
+}
+
+func openURL(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const htmlSnapshotTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>wander %s</title>
+<style>
+:root { --fg: #e5e5e5; --bg: #1e1e1e; }
+body { margin: 0; color: var(--fg); background-color: var(--bg); }
+pre { margin: 1em; font-family: monospace; }
+</style>
+</head>
+<body>
+<pre>%s</pre>
+</body>
+</html>
+`
+
+// saveHTMLSnapshot writes the rendered view to a standalone, timestamped HTML file, preserving colors
+func saveHTMLSnapshot(view string) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		path := fmt.Sprintf("wander-%s.html", now.Format("2006-01-02T15-04-05"))
+		content := fmt.Sprintf(htmlSnapshotTemplate, now.Format(time.RFC3339), formatter.ANSIToHTML(view))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return message.ToastMsg{Message: fmt.Sprintf("Saved snapshot to %s", path)}
+	}
+}
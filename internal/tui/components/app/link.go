@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+)
+
+// currentLink identifies the current view, to open it on startup with --open
+func (m Model) currentLink() nomad.Link {
+	l := nomad.Link{
+		Page:      m.currentPage,
+		Namespace: m.config.Namespace,
+		Region:    m.config.Region,
+		Filter:    m.getCurrentPageModel().FilterValue(),
+	}
+	switch m.currentPage {
+	case nomad.JobSpecPage, nomad.AllocationsPage:
+		l.JobID, l.Namespace = m.jobID, m.jobNamespace
+	case nomad.AllocSpecPage, nomad.LogsPage, nomad.TaskEventsPage:
+		l.AllocID, l.TaskName, l.LogType, l.Namespace = m.alloc.ID, m.taskName, m.logType, m.alloc.Namespace
+	case nomad.NodeSpecPage:
+		l.NodeID = m.nodeID
+	}
+	return l
+}
+
+// linkedAllocationMsg is the allocation of a link to an allocation page, fetched once the program starts
+type linkedAllocationMsg struct {
+	link  nomad.Link
+	alloc *api.Allocation
+	err   error
+}
+
+// openLink shows the linked view instead of the jobs page. Allocation pages need the allocation, which may be gone, so
+// the returned command fetches it and the page is shown on the resulting linkedAllocationMsg.
+func (m *Model) openLink(l nomad.Link) tea.Cmd {
+	switch l.Page {
+	case nomad.JobSpecPage, nomad.AllocationsPage:
+		m.jobID, m.jobNamespace = l.JobID, l.Namespace
+	case nomad.AllocSpecPage, nomad.LogsPage, nomad.TaskEventsPage:
+		client := m.client
+		return func() tea.Msg {
+			alloc, _, err := client.Allocations().Info(l.AllocID, nil)
+			return linkedAllocationMsg{link: l, alloc: alloc, err: err}
+		}
+	case nomad.NodeSpecPage:
+		m.nodeID = l.NodeID
+	}
+	m.setPage(l.Page)
+	return nil
+}
+
+// showLinkedAllocation shows the page of a link to an allocation, staying on the jobs page if it couldn't be fetched
+func (m *Model) showLinkedAllocation(msg linkedAllocationMsg) {
+	if msg.err != nil {
+		m.warnings = append(m.warnings, fmt.Sprintf("Could not open link, showing jobs instead: allocation %s: %v", msg.link.AllocID, msg.err))
+		return
+	}
+	m.alloc, m.taskName, m.logType = *msg.alloc, msg.link.TaskName, msg.link.LogType
+	if m.taskName == "" {
+		m.taskName = nomad.DefaultTaskName(*msg.alloc, m.config.DefaultLogTasks)
+	}
+	m.jobID, m.jobNamespace = msg.alloc.JobID, msg.alloc.Namespace
+	m.setPage(msg.link.Page)
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"io"
+	"sync"
+)
+
+// Output is a terminal shared by the program's renderer and anything else writing to it, e.g. watch notifications and
+// OSC52 copies. Writes are serialized, so a rendered frame is written whole rather than interleaved with other writes.
+type Output struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewOutput(out io.Writer) *Output {
+	return &Output{out: out}
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.out.Write(p)
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const defaultPager = "less"
+
+type pagerReadyMsg struct {
+	path string
+}
+
+type pagerFinishedMsg struct {
+	path string
+	err  error
+}
+
+// writeToPagerFile writes content to a temporary file to be opened in the pager
+func writeToPagerFile(content string) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.CreateTemp("", "wander-logs-*.log")
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		defer f.Close()
+
+		if _, err = f.WriteString(content); err != nil {
+			_ = os.Remove(f.Name())
+			return message.ToastMsg{Err: err}
+		}
+		return pagerReadyMsg{path: f.Name()}
+	}
+}
+
+// openInPager opens the file at path in $PAGER, suspending the program until the pager exits. Over ssh, the pager
+// is always less in its secure mode with the server's environment, as anything the client chose would run on the
+// server. Only the session's TERM is taken from the client, so less matches its terminal.
+func openInPager(path string, serving bool, sshEnviron []string) tea.Cmd {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	var env []string
+	if serving {
+		pager = []string{defaultPager}
+		env = append(os.Environ(), "LESSSECURE=1")
+		if term := getEnv("TERM", sshEnviron); term != "" {
+			env = append(env, "TERM="+term)
+		}
+	}
+	if len(pager) == 0 {
+		pager = []string{defaultPager}
+	}
+	c := exec.Command(pager[0], append(pager[1:], path)...)
+	c.Env = env
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return pagerFinishedMsg{path: path, err: err}
+	})
+}
+
+func getEnv(key string, environ []string) string {
+	for _, kv := range environ {
+		if k, v, found := strings.Cut(kv, "="); found && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func pagerFinished(msg pagerFinishedMsg) tea.Cmd {
+	_ = os.Remove(msg.path)
+	if msg.err != nil {
+		return toastCmd(message.ToastMsg{Err: fmt.Errorf("pager: %w", msg.err)})
+	}
+	return nil
+}
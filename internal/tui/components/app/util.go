@@ -1,9 +1,25 @@
 package app
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/state"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 )
 
 var (
@@ -18,7 +34,93 @@ func nextUpdateID() int {
 	return updateID
 }
 
-func (c Config) client() (*api.Client, error) {
+// jitterRand is seeded per process so that instances started together don't jitter identically. It's shared by the
+// programs of every ssh session when serving, so jitterRandMtx guards it.
+var (
+	jitterRand    = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterRandMtx sync.Mutex
+)
+
+// withJitter randomly varies d by up to percent of it either way
+func withJitter(d time.Duration, percent int) time.Duration {
+	if d <= 0 || percent <= 0 {
+		return d
+	}
+	maxJitter := int64(d) * int64(percent) / 100
+	if maxJitter <= 0 {
+		return d
+	}
+	jitterRandMtx.Lock()
+	defer jitterRandMtx.Unlock()
+	return d + time.Duration(jitterRand.Int63n(2*maxJitter+1)-maxJitter)
+}
+
+// retryDelay doubles the delay for each previous retry, up to a maximum
+func retryDelay(retries int) time.Duration {
+	delay := constants.InitialConnectionRetryDelay
+	for i := 0; i < retries && delay < constants.MaxConnectionRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > constants.MaxConnectionRetryDelay {
+		return constants.MaxConnectionRetryDelay
+	}
+	return delay
+}
+
+func getNamespaceColor(c Config, namespace string) string {
+	if color, exists := c.NamespaceColors[namespace]; exists {
+		return color
+	}
+	return c.DefaultNamespaceColor
+}
+
+// isTimeout is true if err is from a Nomad API request exceeding the API timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout awaiting response headers")
+}
+
+// isNetworkError is true if err is from failing to reach Nomad, rather than from Nomad rejecting a request
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return isTimeout(err) || errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// overlayBottom replaces the bottom lines of view with overlay
+func overlayBottom(view, overlay string) string {
+	lines := strings.Split(view, "\n")
+	lines = lines[:max(0, len(lines)-lipgloss.Height(overlay))]
+	return strings.Join(lines, "\n") + "\n" + overlay
+}
+
+// getLogo resolves the logo config: empty or "default" for the wander logo, "none" for no logo, otherwise custom text
+func getLogo(logo string) string {
+	switch strings.TrimSpace(logo) {
+	case "", "default":
+		return constants.LogoString
+	case "none":
+		return ""
+	}
+	return strings.TrimRight(logo, "\n")
+}
+
+func saveStateCmd(s state.State, path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := s.Save(path); err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+func toastCmd(msg message.ToastMsg) tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
+func (c Config) Client() (*api.Client, error) {
 	config := &api.Config{
 		Address:   c.URL,
 		SecretID:  c.Token,
@@ -34,6 +136,12 @@ func (c Config) client() (*api.Client, error) {
 		},
 	}
 
+	httpClient, err := c.httpClient(config.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	config.HttpClient = httpClient
+
 	if auth := c.HTTPAuth; auth != "" {
 		var username, password string
 		if strings.Contains(auth, ":") {
@@ -50,5 +158,69 @@ func (c Config) client() (*api.Client, error) {
 		}
 	}
 
-	return api.NewClient(config)
+	client, err := api.NewClient(config)
+	if err == nil && c.responses != nil {
+		config.HttpClient.Transport = capturingTransport{base: httpClient.Transport, recorder: c.responses}
+	}
+	return client, err
+}
+
+// httpClient returns the HTTP client shared by every request from a Nomad client, with tlsConfig applied to it
+func (c Config) httpClient(tlsConfig *api.TLSConfig) (*http.Client, error) {
+	// a single transport shared by every request from this client keeps connections alive between page loads and
+	// updates, avoiding a TLS handshake per request
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = constants.MaxIdleConnsPerHost
+	transport.TLSClientConfig = &tls.Config{}
+	if c.APITimeout > 0 {
+		// time out waiting for a response rather than for the whole request, so long-lived event streams continue
+		transport.ResponseHeaderTimeout = c.APITimeout
+	}
+	// the Nomad client requires an *http.Transport, so rather than wrapping it, requests are logged from the proxy
+	// lookup, which runs for every request
+	proxy := transport.Proxy
+	transport.Proxy = func(r *http.Request) (*url.URL, error) {
+		dev.Info(fmt.Sprintf("api %s %s", r.Method, r.URL))
+		return proxy(r)
+	}
+	// the Nomad client only configures TLS on clients it creates itself, so it's done here before installing this one
+	httpClient := &http.Client{Transport: transport}
+	if err := api.ConfigureTLS(httpClient, tlsConfig); err != nil {
+		return nil, err
+	}
+	return httpClient, nil
+}
+
+type disconnectCheckMsg struct{}
+
+func checkDisconnectWithDelay() tea.Cmd {
+	return tea.Tick(constants.DisconnectCheckInterval, func(t time.Time) tea.Msg { return disconnectCheckMsg{} })
+}
+
+type dimCheckMsg struct{}
+
+func checkDimWithDelay() tea.Cmd {
+	return tea.Tick(constants.DimCheckInterval, func(t time.Time) tea.Msg { return dimCheckMsg{} })
+}
+
+// clampSize limits size to maxSize, unless maxSize is 0
+func clampSize(size, maxSize int) int {
+	if maxSize > 0 && size > maxSize {
+		return maxSize
+	}
+	return size
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
 }
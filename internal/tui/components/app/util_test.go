@@ -0,0 +1,129 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/nomad/api"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and its key to dir, returning their paths and the parsed certificate
+func writeTestCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "wander test"},
+		DNSNames:              []string{"nomad.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath, cert
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	certPath, keyPath, cert := writeTestCert(t, t.TempDir())
+	c := Config{}
+	httpClient, err := c.httpClient(&api.TLSConfig{
+		CACert:        certPath,
+		ClientCert:    certPath,
+		ClientKey:     keyPath,
+		TLSServerName: "nomad.test",
+		Insecure:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tlsConfig := httpClient.Transport.(*http.Transport).TLSClientConfig
+	if tlsConfig.RootCAs == nil {
+		t.Fatal("expected the CA to be configured")
+	}
+	if _, err = cert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs, DNSName: "nomad.test"}); err != nil {
+		t.Errorf("expected the configured CA to verify its certificate: %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 || !bytes.Equal(tlsConfig.Certificates[0].Certificate[0], cert.Raw) {
+		t.Errorf("expected the client certificate to be configured, got %d certificates", len(tlsConfig.Certificates))
+	}
+	if tlsConfig.ServerName != "nomad.test" {
+		t.Errorf("expected server name %q, got %q", "nomad.test", tlsConfig.ServerName)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("expected verification to be skipped")
+	}
+}
+
+func TestHTTPClientTLSErrors(t *testing.T) {
+	certPath, _, _ := writeTestCert(t, t.TempDir())
+	c := Config{}
+	if _, err := c.httpClient(&api.TLSConfig{ClientCert: certPath}); err == nil {
+		t.Error("expected an error for a client certificate without a key")
+	}
+	if _, err := c.httpClient(&api.TLSConfig{CACert: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected an error for a missing CA certificate")
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"connection refused", &url.Error{Op: "Get", URL: "http://nomad", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"wrapped connection refused", fmt.Errorf("fetching jobs: %w", syscall.ECONNREFUSED), true},
+		{"timeout", fmt.Errorf("fetching jobs: %w", context.DeadlineExceeded), true},
+		{"permission denied", errors.New("Unexpected response code: 403 (Permission denied)"), false},
+		{"not found", errors.New("Unexpected response code: 404 (job not found)"), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := isNetworkError(test.err); actual != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/nomad"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+type NotifyMethod string
+
+const (
+	BellNotify NotifyMethod = "bell"
+	// OSC9Notify asks the terminal for a desktop notification, falling back to nothing in terminals without support
+	OSC9Notify NotifyMethod = "osc9"
+)
+
+// failedTransition is the transition of a watched job with a new failed allocation
+const failedTransition = "failed"
+
+// JobWatch notifies when the job's status changes to one of On, or to any status if On is empty. On can include
+// "failed" for new failed allocations, and "missing" for the job no longer existing.
+type JobWatch struct {
+	Job       string       `mapstructure:"job"`
+	Namespace string       `mapstructure:"namespace"`
+	Notify    NotifyMethod `mapstructure:"notify"`
+	On        []string     `mapstructure:"on"`
+}
+
+type watchCheckMsg struct{}
+
+func ValidateJobWatch(w JobWatch) error {
+	if w.Job == "" {
+		return fmt.Errorf("each watch requires a job")
+	}
+	if w.Notify != "" && w.Notify != BellNotify && w.Notify != OSC9Notify {
+		return fmt.Errorf("watch %s: notify %s must be bell or osc9", w.Job, w.Notify)
+	}
+	return nil
+}
+
+func watchKey(w JobWatch) string {
+	namespace := w.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return nomad.JobKey(w.Job, namespace)
+}
+
+func checkWatchesWithDelay(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg { return watchCheckMsg{} })
+}
+
+func (m Model) fetchWatchedJobStates() tea.Cmd {
+	var keys []string
+	for _, w := range m.config.Watches {
+		keys = append(keys, watchKey(w))
+	}
+	return nomad.FetchJobStates(m.client, keys)
+}
+
+// watchTransitions lists the transitions of watched jobs since their last check that they are watched for. Jobs seen
+// for the first time have no transitions.
+func watchTransitions(w JobWatch, prev, curr nomad.JobState) []string {
+	var transitions []string
+	if curr.Status != prev.Status {
+		transitions = append(transitions, curr.Status)
+	}
+	if curr.Failed > prev.Failed {
+		transitions = append(transitions, failedTransition)
+	}
+	if len(w.On) == 0 {
+		return transitions
+	}
+	var watched []string
+	for _, t := range transitions {
+		for _, on := range w.On {
+			if strings.EqualFold(t, on) {
+				watched = append(watched, t)
+			}
+		}
+	}
+	return watched
+}
+
+// notifyWatchTransitions alerts the terminal for each watched transition, along with a toast describing them
+func (m *Model) notifyWatchTransitions(states map[string]nomad.JobState) tea.Cmd {
+	if m.watchedJobStates == nil {
+		m.watchedJobStates = make(map[string]nomad.JobState)
+	}
+	var cmds []tea.Cmd
+	var descriptions []string
+	for _, w := range m.config.Watches {
+		key := watchKey(w)
+		curr, ok := states[key]
+		if !ok {
+			continue
+		}
+		prev, seen := m.watchedJobStates[key]
+		m.watchedJobStates[key] = curr
+		if !seen {
+			continue
+		}
+		for _, t := range watchTransitions(w, prev, curr) {
+			description := fmt.Sprintf("job %s %s", w.Job, t)
+			if t == failedTransition {
+				description = fmt.Sprintf("job %s has %d new failed allocations", w.Job, curr.Failed-prev.Failed)
+			} else if prev.Status != "" {
+				description = fmt.Sprintf("job %s %s -> %s", w.Job, prev.Status, t)
+			}
+			cmds = append(cmds, notifyCmd(w.Notify, "wander: "+description, m.config.SSHOutput))
+			descriptions = append(descriptions, description)
+		}
+	}
+	if len(descriptions) == 0 {
+		return nil
+	}
+	cmds = append(cmds, toastCmd(message.ToastMsg{Message: "Watched " + strings.Join(descriptions, ", ")}))
+	return tea.Batch(cmds...)
+}
+
+// notifyCmd writes to the terminal outside of the view, so the ssh client's terminal is notified when serving over ssh.
+// Each notification is a single write, which the terminal, or the ssh session's Output, keeps whole between the
+// renderer's frames. Notifying can't fail in a way that can be detected, so errors are ignored.
+func notifyCmd(method NotifyMethod, text string, sshOutput io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		out := io.Writer(os.Stdout)
+		if sshOutput != nil {
+			out = sshOutput
+		}
+		switch method {
+		case OSC9Notify:
+			// control characters would end the sequence early
+			text = strings.Map(func(r rune) rune {
+				if r < ' ' {
+					return ' '
+				}
+				return r
+			}, text)
+			_, _ = fmt.Fprintf(out, "\x1b]9;%s\a", text)
+		default:
+			_, _ = fmt.Fprint(out, "\a")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package confirm
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/tui/style"
+)
+
+var (
+	keyMap = getKeyMap()
+)
+
+// Model asks the user to confirm an action before running it
+type Model struct {
+	prompt    string
+	onConfirm tea.Cmd
+	keyMap    confirmKeyMap
+	width     int
+	Visible   bool
+
+	// required, if set, must be typed and submitted to confirm, instead of pressing the confirm key
+	required string
+	typed    string
+}
+
+func New(prompt string, onConfirm tea.Cmd, width int) Model {
+	return Model{
+		prompt:    prompt,
+		onConfirm: onConfirm,
+		keyMap:    keyMap,
+		width:     width,
+		Visible:   true,
+	}
+}
+
+// NewTyped asks the user to type required to confirm, for actions where a keypress is too easy to make by mistake
+func NewTyped(prompt, required string, onConfirm tea.Cmd, width int) Model {
+	m := New(prompt, onConfirm, width)
+	m.required = required
+	return m
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	dev.Debug(fmt.Sprintf("confirm %T", msg))
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.required != "" {
+			return m.updateTyped(msg)
+		}
+		switch {
+		case key.Matches(msg, m.keyMap.Confirm):
+			m.Visible = false
+			return m, m.onConfirm
+		case key.Matches(msg, m.keyMap.Cancel):
+			m.Visible = false
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateTyped(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if m.typed == m.required {
+			m.Visible = false
+			return m, m.onConfirm
+		}
+	case tea.KeyEsc:
+		m.Visible = false
+	case tea.KeyBackspace:
+		if len(m.typed) > 0 {
+			runes := []rune(m.typed)
+			m.typed = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.typed += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	if !m.Visible {
+		return ""
+	}
+	if m.required != "" {
+		prompt := fmt.Sprintf("%s Type %s and press enter to confirm, esc to cancel: %s", m.prompt, m.required, m.typed)
+		return style.ConfirmPrompt.Copy().Width(m.width).Render(prompt)
+	}
+	help := fmt.Sprintf("%s/%s", m.keyMap.Confirm.Help().Key, m.keyMap.Cancel.Help().Key)
+	return style.ConfirmPrompt.Copy().Width(m.width).Render(fmt.Sprintf("%s (%s)", m.prompt, help))
+}
+
+func (m *Model) SetWidth(width int) {
+	m.width = width
+}
+
+func (m Model) ViewHeight() int {
+	return lipgloss.Height(m.View())
+}
@@ -0,0 +1,21 @@
+package confirm
+
+import "github.com/charmbracelet/bubbles/key"
+
+type confirmKeyMap struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}
+
+func getKeyMap() confirmKeyMap {
+	return confirmKeyMap{
+		Confirm: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n/esc", "cancel"),
+		),
+	}
+}
@@ -2,10 +2,12 @@ package filter
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/robinovitch61/wander/internal/dev"
+	"github.com/robinovitch61/wander/internal/tui/constants"
 	"github.com/robinovitch61/wander/internal/tui/style"
 )
 
@@ -15,8 +17,14 @@ var (
 
 type Model struct {
 	prefix    string
+	err       string
 	keyMap    filterKeyMap
 	textinput textinput.Model
+
+	// history is the recently applied filters, most recent last. historyIdx is the recalled entry, or
+	// len(history) if not recalling.
+	history    []string
+	historyIdx int
 }
 
 func New(prefix string) Model {
@@ -32,6 +40,17 @@ func New(prefix string) Model {
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	dev.Debug(fmt.Sprintf("filter %T", msg))
+	if msg, ok := msg.(tea.KeyMsg); ok && m.textinput.Focused() {
+		switch {
+		case key.Matches(msg, m.keyMap.Prev):
+			m.recall(m.historyIdx - 1)
+			return m, nil
+		case key.Matches(msg, m.keyMap.Next):
+			m.recall(m.historyIdx + 1)
+			return m, nil
+		}
+	}
+
 	var cmd tea.Cmd
 	m.textinput, cmd = m.textinput.Update(msg)
 	return m, cmd
@@ -66,10 +85,15 @@ func (m Model) View() string {
 	}
 	filterString := m.textinput.View()
 	filterStringStyle := m.textinput.TextStyle.Copy().MarginLeft(1).PaddingLeft(1).PaddingRight(0)
+	var filterError string
+	if m.err != "" {
+		filterError = style.FilterError.Render(m.err)
+	}
 	return lipgloss.JoinHorizontal(
 		lipgloss.Center,
 		style.FilterPrefix.Render(m.prefix),
 		filterStringStyle.Render(filterString),
+		filterError,
 	)
 }
 
@@ -77,6 +101,47 @@ func (m Model) Value() string {
 	return m.textinput.Value()
 }
 
+func (m *Model) SetHistory(history []string) {
+	m.history = history
+	m.historyIdx = len(history)
+}
+
+func (m Model) History() []string {
+	return m.history
+}
+
+// AddToHistory adds value as the most recent filter, moving it if it was already in the history
+func (m *Model) AddToHistory(value string) {
+	var history []string
+	for _, h := range m.history {
+		if h != value {
+			history = append(history, h)
+		}
+	}
+	history = append(history, value)
+	if len(history) > constants.FilterHistorySize {
+		history = history[len(history)-constants.FilterHistorySize:]
+	}
+	m.SetHistory(history)
+}
+
+func (m *Model) recall(idx int) {
+	if idx < 0 || idx > len(m.history) {
+		return
+	}
+	m.historyIdx = idx
+	if idx == len(m.history) {
+		m.textinput.SetValue("")
+	} else {
+		m.textinput.SetValue(m.history[idx])
+	}
+	m.textinput.CursorEnd()
+}
+
+func (m *Model) SetValue(value string) {
+	m.textinput.SetValue(value)
+}
+
 func (m Model) ViewHeight() int {
 	return lipgloss.Height(m.View())
 }
@@ -85,11 +150,17 @@ func (m *Model) SetPrefix(prefix string) {
 	m.prefix = prefix
 }
 
+// SetError shows err next to the filter, e.g. for an invalid filter expression. An empty string clears it.
+func (m *Model) SetError(err string) {
+	m.err = err
+}
+
 func (m Model) Focused() bool {
 	return m.textinput.Focused()
 }
 
 func (m *Model) Focus() {
+	m.historyIdx = len(m.history)
 	m.textinput.SetCursorMode(textinput.CursorBlink)
 	m.textinput.Focus()
 }
@@ -6,6 +6,8 @@ type filterKeyMap struct {
 	Forward key.Binding
 	Back    key.Binding
 	Filter  key.Binding
+	Prev    key.Binding
+	Next    key.Binding
 }
 
 func getKeyMap() filterKeyMap {
@@ -22,5 +24,13 @@ func getKeyMap() filterKeyMap {
 			key.WithKeys("/"),
 			key.WithHelp("/", "filter"),
 		),
+		Prev: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous filter"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next filter"),
+		),
 	}
 }
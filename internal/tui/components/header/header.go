@@ -7,11 +7,17 @@ import (
 )
 
 type Model struct {
-	logo, logoColor, nomadUrl, version, KeyHelp string
+	logo, logoColor, nomadUrl, profile, version, KeyHelp string
+	borderColor                                          string
+	width                                                int
+	// namespace is shown when set, as it can be switched without restarting
+	namespace string
+	// serverVersion is the connected Nomad's version, shown once detected
+	serverVersion string
 }
 
-func New(logo string, logoColor string, nomadUrl, version, keyHelp string) (m Model) {
-	return Model{logo: logo, logoColor: logoColor, nomadUrl: nomadUrl, version: version, KeyHelp: keyHelp}
+func New(logo string, logoColor string, nomadUrl, profile, version, keyHelp string) (m Model) {
+	return Model{logo: logo, logoColor: logoColor, nomadUrl: nomadUrl, profile: profile, version: version, KeyHelp: keyHelp}
 }
 
 func (m Model) View() string {
@@ -19,13 +25,51 @@ func (m Model) View() string {
 	if m.logoColor != "" {
 		logoStyle.Foreground(lipgloss.Color(m.logoColor))
 	}
-	logo := logoStyle.Render(m.logo)
+	if m.width > 0 {
+		// truncate over-wide custom logos rather than breaking the layout
+		logoStyle = logoStyle.Copy().MaxWidth(m.width)
+	}
 	clusterUrl := style.ClusterUrl.Render(m.nomadUrl)
-	left := style.Header.Render(lipgloss.JoinVertical(lipgloss.Center, logo, m.version, clusterUrl))
+	var leftRows []string
+	if m.logo != "" {
+		leftRows = append(leftRows, logoStyle.Render(m.logo))
+	}
+	leftRows = append(leftRows, m.version, clusterUrl)
+	if m.serverVersion != "" {
+		leftRows = append(leftRows, "nomad "+m.serverVersion)
+	}
+	if m.profile != "" {
+		leftRows = append(leftRows, "profile: "+m.profile)
+	}
+	if m.namespace != "" {
+		leftRows = append(leftRows, "namespace: "+m.namespace)
+	}
+	headerStyle := style.Header
+	if m.borderColor != "" {
+		headerStyle = headerStyle.Copy().BorderForeground(lipgloss.Color(m.borderColor))
+	}
+	left := headerStyle.Render(lipgloss.JoinVertical(lipgloss.Center, leftRows...))
 	styledKeyHelp := style.KeyHelp.Render(m.KeyHelp)
 	return lipgloss.JoinHorizontal(lipgloss.Center, left, styledKeyHelp)
 }
 
+// SetBorderColor tints the header border, e.g. to distinguish namespaces. An empty string uses the default color.
+func (m *Model) SetBorderColor(c string) {
+	m.borderColor = c
+}
+
+func (m *Model) SetNamespace(namespace string) {
+	m.namespace = namespace
+}
+
+func (m *Model) SetServerVersion(version string) {
+	m.serverVersion = version
+}
+
+func (m *Model) SetWidth(width int) {
+	m.width = width
+}
+
 func (m Model) ViewHeight() int {
 	return len(strings.Split(m.View(), "\n"))
 }
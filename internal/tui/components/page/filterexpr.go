@@ -0,0 +1,133 @@
+package page
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MetaFieldPrefix prefixes the keys of meta fields, e.g. meta.team=payments, which are valid even if no row has them
+// as meta keys vary by row
+const MetaFieldPrefix = "meta."
+
+var (
+	// a term starting with a field name followed by an operator-like character is treated as structured
+	structuredTermStart = regexp.MustCompile(`^[A-Za-z_]+(\.[^\s=~!<>]+)?[=~!<>]`)
+	structuredTerm      = regexp.MustCompile(`^([A-Za-z_]+(?:\.[^\s=~!<>]+)?)(=~|=|~)(.*)$`)
+)
+
+type rowPredicate func(Row) bool
+
+// filterTerm is a single field, operator and value in a structured filter expression
+type filterTerm struct {
+	field, operator, value string
+	re                     *regexp.Regexp
+}
+
+func (t filterTerm) matches(r Row) bool {
+	switch t.operator {
+	case "=":
+		return r.Fields[t.field] == t.value
+	case "~":
+		return strings.Contains(r.Fields[t.field], t.value)
+	case "=~":
+		return t.re.MatchString(r.Fields[t.field])
+	}
+	return false
+}
+
+// parseFilterTerms parses the syntax of a structured filter expression, without validating field names
+func parseFilterTerms(filter string) ([]filterTerm, error) {
+	var terms []filterTerm
+	for _, term := range strings.Fields(filter) {
+		matches := structuredTerm.FindStringSubmatch(term)
+		if matches == nil {
+			if structuredTermStart.MatchString(term) {
+				return nil, fmt.Errorf("invalid operator in %s, use =, ~ or =~", term)
+			}
+			return nil, fmt.Errorf("%s is not of the form field=value, field~value or field=~regex", term)
+		}
+
+		t := filterTerm{field: normalizeField(matches[1]), operator: matches[2], value: matches[3]}
+		if t.operator == "=~" {
+			re, err := regexp.Compile(t.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex in %s", term)
+			}
+			t.re = re
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// parseFilterExpression parses filters like `status=running type=service name~api id=~^web-` into a predicate
+// matching rows where every term holds. Operators are exact (=), substring (~) and regex (=~). Returns a nil
+// predicate if the filter is not a structured expression and should be matched as plain text.
+func parseFilterExpression(filter string, fields []string) (rowPredicate, error) {
+	terms := strings.Fields(filter)
+	isStructured := false
+	for _, term := range terms {
+		if structuredTermStart.MatchString(term) {
+			isStructured = true
+			break
+		}
+	}
+	if !isStructured {
+		return nil, nil
+	}
+
+	parsedTerms, err := parseFilterTerms(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	validFields := make(map[string]bool)
+	for _, f := range fields {
+		validFields[f] = true
+	}
+	for _, t := range parsedTerms {
+		if !validFields[t.field] && !strings.HasPrefix(t.field, MetaFieldPrefix) {
+			return nil, fmt.Errorf("unknown field %s, expected one of %s", t.field, strings.Join(sortedFields(fields), ", "))
+		}
+	}
+
+	return func(r Row) bool {
+		return allTermsMatch(parsedTerms, r)
+	}, nil
+}
+
+func allTermsMatch(terms []filterTerm, r Row) bool {
+	for _, t := range terms {
+		if !t.matches(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeField lowercases field names, except for meta keys which are case-sensitive
+func normalizeField(field string) string {
+	if split := strings.SplitN(field, ".", 2); len(split) == 2 {
+		return strings.ToLower(split[0]) + "." + split[1]
+	}
+	return strings.ToLower(field)
+}
+
+func rowFields(rows []Row) []string {
+	if len(rows) == 0 {
+		return []string{}
+	}
+	var fields []string
+	for f := range rows[0].Fields {
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func sortedFields(fields []string) []string {
+	sorted := append([]string{}, fields...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,50 @@
+package page
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HighlightRule styles rows matching a structured filter expression, e.g. `status=dead`
+type HighlightRule struct {
+	terms []filterTerm
+	Style lipgloss.Style
+}
+
+// NewHighlightRule parses the match expression, erroring on invalid syntax. Field names can't be validated until
+// rows are loaded, so a rule with an unknown field never matches.
+func NewHighlightRule(match, color string) (HighlightRule, error) {
+	if color == "" {
+		return HighlightRule{}, fmt.Errorf("highlight rule %s requires a color", match)
+	}
+	terms, err := parseFilterTerms(match)
+	if err != nil {
+		return HighlightRule{}, fmt.Errorf("highlight rule %s: %w", match, err)
+	}
+	if len(terms) == 0 {
+		return HighlightRule{}, fmt.Errorf("highlight rule requires a match expression")
+	}
+	return HighlightRule{terms: terms, Style: lipgloss.NewStyle().Foreground(lipgloss.Color(color))}, nil
+}
+
+func (h HighlightRule) Matches(r Row) bool {
+	return allTermsMatch(h.terms, r)
+}
+
+// getRowStyles maps the index of each row to its own style if set, otherwise the style of the first rule it matches
+func getRowStyles(rows []Row, rules []HighlightRule) map[int]lipgloss.Style {
+	styles := make(map[int]lipgloss.Style)
+	for idx, r := range rows {
+		if r.Style != nil {
+			styles[idx] = *r.Style
+			continue
+		}
+		for _, rule := range rules {
+			if rule.Matches(r) {
+				styles[idx] = rule.Style
+				break
+			}
+		}
+	}
+	return styles
+}
@@ -2,7 +2,6 @@ package page
 
 import (
 	"fmt"
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,6 +13,7 @@ import (
 	"github.com/robinovitch61/wander/internal/tui/constants"
 	"github.com/robinovitch61/wander/internal/tui/keymap"
 	"github.com/robinovitch61/wander/internal/tui/message"
+	"regexp"
 	"strings"
 )
 
@@ -22,6 +22,13 @@ type Config struct {
 	FilterPrefix, LoadingString                            string
 	CopySavePath, SelectionEnabled, WrapText, RequestInput bool
 	ViewportConditionalStyle                               map[string]lipgloss.Style
+	// PinningEnabled allows pinning selected lines as references that stay visible while scrolling
+	PinningEnabled bool
+	HighlightRules []HighlightRule
+	// MaxRows limits the rows of content shown if above 0, otherwise they fill the page
+	MaxRows int
+	// Sort orders rows by their fields as they load, otherwise they keep the order they loaded in
+	Sort []SortKey
 }
 
 type Model struct {
@@ -31,11 +38,15 @@ type Model struct {
 
 	viewport viewport.Model
 	filter   filter.Model
+	// filterRegex matches plain text filters as a regex rather than a substring
+	filterRegex bool
 
 	loadingString string
 	loading       bool
 
-	copySavePath bool
+	copySavePath   bool
+	highlightRules []HighlightRule
+	sort           []SortKey
 
 	doesRequestInput bool
 	textinput        textinput.Model
@@ -50,6 +61,8 @@ func New(c Config) Model {
 	pageViewport.SetSelectionEnabled(c.SelectionEnabled)
 	pageViewport.SetWrapText(c.WrapText)
 	pageViewport.ConditionalStyle = c.ViewportConditionalStyle
+	pageViewport.SetPinningEnabled(c.PinningEnabled)
+	pageViewport.SetMaxContentHeight(c.MaxRows)
 
 	needsNewInput := false
 	var pageTextInput textinput.Model
@@ -69,6 +82,8 @@ func New(c Config) Model {
 		loadingString:    c.LoadingString,
 		loading:          true,
 		copySavePath:     c.CopySavePath,
+		highlightRules:   c.HighlightRules,
+		sort:             c.Sort,
 		doesRequestInput: c.RequestInput,
 		textinput:        pageTextInput,
 		needsNewInput:    needsNewInput,
@@ -109,16 +124,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case viewport.SaveStatusMsg:
-		if m.copySavePath {
+		if m.copySavePath && msg.FullPath != "" {
 			cmds = append(cmds, func() tea.Msg {
-				_ = clipboard.WriteAll(msg.FullPath)
-				return nil
+				return message.CopyMsg{Text: msg.FullPath, Description: msg.FullPath}
 			})
 		}
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
 
-	case toast.TimeoutMsg:
+	case message.ToastMsg, toast.TimeoutMsg:
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
 
@@ -135,6 +149,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			switch {
 			case key.Matches(msg, keymap.KeyMap.Forward):
 				m.filter.Blur()
+				if value := m.filter.Value(); value != "" {
+					m.filter.AddToHistory(value)
+					history := m.filter.History()
+					cmds = append(cmds, func() tea.Msg { return message.FilterHistoryChangedMsg{History: history} })
+				}
 			}
 		} else {
 			switch {
@@ -183,12 +202,18 @@ func (m *Model) SetWindowSize(width, height int) {
 
 func (m *Model) SetHeader(header []string) {
 	m.viewport.SetHeader(header)
+	m.viewport.SetFrozenColumnWidth(firstColumnWidth(header))
 }
 
 func (m *Model) SetInputPrefix(p string) {
 	m.inputPrefix = p
 }
 
+func (m *Model) SetInputValue(v string) {
+	m.textinput.SetValue(v)
+	m.textinput.CursorEnd()
+}
+
 func (m *Model) SetViewportStyle(headerStyle, contentStyle lipgloss.Style) {
 	m.viewport.HeaderStyle = headerStyle
 	m.viewport.ContentStyle = contentStyle
@@ -199,7 +224,7 @@ func (m *Model) SetLoading(isLoading bool) {
 }
 
 func (m *Model) SetAllPageData(allPageData []Row) {
-	m.pageData.All = allPageData
+	m.pageData.All = sortRows(allPageData, m.sort)
 	m.updateViewport()
 }
 
@@ -207,6 +232,21 @@ func (m *Model) SetFilterPrefix(prefix string) {
 	m.filter.SetPrefix(prefix)
 }
 
+func (m *Model) SetFilterHistory(history []string) {
+	m.filter.SetHistory(history)
+}
+
+// SetFilter applies a filter without focusing the filter input
+func (m *Model) SetFilter(value string) {
+	m.filter.SetValue(value)
+	m.filter.Blur()
+	m.updateViewport()
+}
+
+func (m *Model) SetViewportSelection(n int) {
+	m.viewport.SetSelectedContentIdx(n)
+}
+
 func (m *Model) SetViewportSelectionToBottom() {
 	m.viewport.SetSelectedContentIdx(len(m.pageData.Filtered) - 1)
 }
@@ -281,6 +321,27 @@ func (m Model) ViewportSelectionAtBottom() bool {
 	return m.viewport.SelectedContentIdx() == len(m.pageData.Filtered)-1
 }
 
+func (m Model) AllPageRows() []Row {
+	return m.pageData.All
+}
+
+func (m Model) FilteredPageRows() []Row {
+	return m.pageData.Filtered
+}
+
+// FilteredContent is the filtered rows, untruncated, e.g. for a pager that can handle long lines
+func (m Model) FilteredContent() string {
+	var lines []string
+	for _, row := range m.pageData.Filtered {
+		if row.Full != "" {
+			lines = append(lines, row.Full)
+			continue
+		}
+		lines = append(lines, row.Row)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) EnteringInput() bool {
 	return m.doesRequestInput && m.needsNewInput
 }
@@ -289,6 +350,19 @@ func (m Model) FilterFocused() bool {
 	return m.filter.Focused()
 }
 
+func (m Model) FilterValue() string {
+	return m.filter.Value()
+}
+
+func (m Model) FilterRegex() bool {
+	return m.filterRegex
+}
+
+func (m *Model) SetFilterRegex(filterRegex bool) {
+	m.filterRegex = filterRegex
+	m.updateViewport()
+}
+
 func (m Model) FilterApplied() bool {
 	return len(m.filter.Value()) > 0
 }
@@ -307,23 +381,69 @@ func (m *Model) clearFilter() {
 }
 
 func (m *Model) updateViewport() {
-	m.viewport.SetStringToHighlight(m.filter.Value())
 	m.updateFilteredData()
 	m.viewport.SetContent(rowsToStrings(m.pageData.Filtered))
+	m.viewport.SetContentStyles(getRowStyles(m.pageData.Filtered, m.highlightRules))
+	m.viewport.SetFullContent(getFullRows(m.pageData.Filtered))
 }
 
 func (m *Model) updateFilteredData() {
+	m.filter.SetError("")
+	m.viewport.SetStringToHighlight(m.filter.Value())
 	if m.filter.Value() == "" {
 		m.pageData.Filtered = m.pageData.All
-	} else {
+		return
+	}
+
+	if fields := rowFields(m.pageData.All); len(fields) > 0 {
+		predicate, err := parseFilterExpression(m.filter.Value(), fields)
+		if err != nil {
+			// leave rows unfiltered while the expression is invalid, e.g. partially typed
+			m.filter.SetError(err.Error())
+			m.viewport.SetStringToHighlight("")
+			m.pageData.Filtered = m.pageData.All
+			return
+		}
+		if predicate != nil {
+			m.viewport.SetStringToHighlight("")
+			var filteredData []Row
+			for _, entry := range m.pageData.All {
+				if predicate(entry) {
+					filteredData = append(filteredData, entry)
+				}
+			}
+			m.pageData.Filtered = filteredData
+			return
+		}
+	}
+
+	if m.filterRegex {
+		re, err := regexp.Compile(m.filter.Value())
+		if err != nil {
+			// leave rows unfiltered while the regex is invalid, e.g. partially typed
+			m.filter.SetError(fmt.Sprintf("invalid regex: %v", err))
+			m.viewport.SetStringToHighlight("")
+			m.pageData.Filtered = m.pageData.All
+			return
+		}
+		m.viewport.SetStringToHighlight("")
 		var filteredData []Row
 		for _, entry := range m.pageData.All {
-			if strings.Contains(entry.Row, m.filter.Value()) {
+			if re.MatchString(entry.Row) {
 				filteredData = append(filteredData, entry)
 			}
 		}
 		m.pageData.Filtered = filteredData
+		return
+	}
+
+	var filteredData []Row
+	for _, entry := range m.pageData.All {
+		if strings.Contains(entry.Row, m.filter.Value()) {
+			filteredData = append(filteredData, entry)
+		}
 	}
+	m.pageData.Filtered = filteredData
 }
 
 func max(a, b int) int {
@@ -332,3 +452,22 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+// firstColumnWidth is the width of a table's first column including padding, found from the column names in the last
+// header row, or 0 if the header isn't a table's
+func firstColumnWidth(header []string) int {
+	if len(header) == 0 {
+		return 0
+	}
+	columnNames := header[len(header)-1]
+	firstName := strings.TrimLeft(columnNames, " ")
+	padding := strings.Index(firstName, constants.TablePadding)
+	if padding <= 0 {
+		return 0
+	}
+	nextColumn := strings.TrimLeft(firstName[padding:], " ")
+	if nextColumn == "" {
+		return 0
+	}
+	return len(columnNames) - len(nextColumn)
+}
@@ -0,0 +1,88 @@
+package page
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortKey orders rows by one of their fields
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSortKeys parses comma-separated fields, each optionally followed by asc or desc, e.g. `status, name` or
+// `started desc`, erroring on fields not in fields
+func ParseSortKeys(s string, fields []string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(s, ",") {
+		words := strings.Fields(strings.ToLower(part))
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("sort %q must be comma-separated fields, each optionally followed by asc or desc", s)
+		}
+		key := SortKey{Field: words[0]}
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("sort direction %s must be asc or desc", words[1])
+			}
+		}
+		if !isSortField(key.Field, fields) {
+			return nil, fmt.Errorf("unknown field %s, fields are %s", key.Field, strings.Join(fields, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func isSortField(field string, fields []string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// sortRows returns the rows stably sorted by keys, comparing numerically where both values are numbers
+func sortRows(rows []Row, keys []SortKey) []Row {
+	if len(keys) == 0 {
+		return rows
+	}
+	sorted := make([]Row, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(x, y int) bool {
+		for _, k := range keys {
+			c := compareFieldValues(sorted[x].Fields[k.Field], sorted[y].Fields[k.Field])
+			if c == 0 {
+				continue
+			}
+			if k.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return sorted
+}
+
+func compareFieldValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
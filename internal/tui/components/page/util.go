@@ -1,7 +1,15 @@
 package page
 
+import "github.com/charmbracelet/lipgloss"
+
 type Row struct {
 	Key, Row string
+	// Fields are the row's values by lowercase column name, enabling structured filter expressions when set
+	Fields map[string]string
+	// Style overrides the viewport's content style for the row when set
+	Style *lipgloss.Style
+	// Full is the row before it was truncated for display, saved instead of Row when set
+	Full string
 }
 
 func (r Row) String() string {
@@ -16,6 +24,17 @@ func rowsToStrings(rows []Row) []string {
 	return strs
 }
 
+// getFullRows are the untruncated rows by index, for rows truncated for display
+func getFullRows(rows []Row) map[int]string {
+	full := make(map[int]string)
+	for idx, row := range rows {
+		if row.Full != "" {
+			full[idx] = row.Full
+		}
+	}
+	return full
+}
+
 type data struct {
 	All, Filtered []Row
 }
@@ -18,6 +18,8 @@ type viewportKeyMap struct {
 	Save         key.Binding
 	CancelSave   key.Binding
 	ConfirmSave  key.Binding
+	Pin          key.Binding
+	NextPin      key.Binding
 }
 
 func GetKeyMap() viewportKeyMap {
@@ -74,5 +76,13 @@ func GetKeyMap() viewportKeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "confirm"),
 		),
+		Pin: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "toggle pin"),
+		),
+		NextPin: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "go to pin"),
+		),
 	}
 }
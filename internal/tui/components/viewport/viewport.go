@@ -10,6 +10,7 @@ import (
 	"github.com/robinovitch61/wander/internal/fileio"
 	"github.com/robinovitch61/wander/internal/tui/components/toast"
 	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/message"
 	"github.com/robinovitch61/wander/internal/tui/style"
 	"strings"
 )
@@ -49,6 +50,8 @@ type Model struct {
 	height int
 	// contentHeight is the height of the viewport in terminal rows, excluding the header and footer
 	contentHeight int
+	// maxContentHeight caps contentHeight if above 0, leaving the rest of the viewport blank
+	maxContentHeight int
 	// maxLineLength is the maximum line length in terminal characters across header and content
 	maxLineLength int
 
@@ -58,19 +61,34 @@ type Model struct {
 	yOffset int
 	// xOffset is the number of columns scrolled right when content lines overflow the viewport and wrapText is false
 	xOffset int
+	// frozenColumnWidth is the width of the leading columns of each line kept in place when scrolled right, e.g. a
+	// table's identifying first column
+	frozenColumnWidth int
 
 	saveDialog textinput.Model
 	toast      toast.Model
 
 	showPrompt bool
 
+	// pinnedLines are content lines pinned as references, the one at pinnedLineIdx shown below the header while
+	// scrolling. Lines rather than indexes are pinned so pins survive filtering and reloading.
+	pinnedLines    []string
+	pinnedLineIdx  int
+	pinningEnabled bool
+
 	HeaderStyle          lipgloss.Style
 	SelectedContentStyle lipgloss.Style
 	HighlightStyle       lipgloss.Style
 	ContentStyle         lipgloss.Style
 	FooterStyle          lipgloss.Style
+	PinnedStyle          lipgloss.Style
 	// ConditionalStyle styles lines containing key with corresponding style in value
 	ConditionalStyle map[string]lipgloss.Style
+	// contentIdxToStyle styles the item at an index of content, overriding ConditionalStyle
+	contentIdxToStyle map[int]lipgloss.Style
+
+	// contentIdxToFull is the untruncated item at an index of content, saved in its place
+	contentIdxToFull map[int]string
 }
 
 func New(width, height int) (m Model) {
@@ -91,6 +109,7 @@ func New(width, height int) (m Model) {
 	m.SelectedContentStyle = style.ViewportSelectedRowStyle
 	m.HighlightStyle = style.ViewportHighlightStyle
 	m.FooterStyle = style.ViewportFooterStyle
+	m.PinnedStyle = style.ViewportPinnedStyle
 	return m
 }
 
@@ -123,11 +142,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		switch msg := msg.(type) {
 		case SaveStatusMsg:
 			if msg.Err != "" {
-				m.toast = toast.New(fmt.Sprintf("Error: %s", msg.Err))
-				m.toast.MessageStyle = style.ErrorToast.Copy().Width(m.width)
+				m.setToast(fmt.Sprintf("Error: %s", msg.Err), style.ErrorToast)
+			} else {
+				m.setToast(msg.SuccessMessage, style.SuccessToast)
+			}
+
+		case message.ToastMsg:
+			if msg.Err != nil {
+				m.setToast(fmt.Sprintf("Error: %s", msg.Err.Error()), style.ErrorToast)
 			} else {
-				m.toast = toast.New(msg.SuccessMessage)
-				m.toast.MessageStyle = style.SuccessToast.Copy().Width(m.width)
+				m.setToast(msg.Message, style.SuccessToast)
 			}
 
 		case tea.KeyMsg:
@@ -201,6 +225,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			case key.Matches(msg, m.keyMap.Save):
 				m.saveDialog.Focus()
 				cmds = append(cmds, textinput.Blink)
+
+			case m.pinningEnabled && key.Matches(msg, m.keyMap.Pin):
+				m.togglePin()
+
+			case m.pinningEnabled && key.Matches(msg, m.keyMap.NextPin):
+				m.goToPin()
 			}
 		}
 	}
@@ -226,6 +256,10 @@ func (m Model) View() string {
 		addLineToViewString(m.HeaderStyle.Render(headerViewLine))
 	}
 
+	if pinnedLine, ok := m.getPinnedLine(); ok {
+		addLineToViewString(m.PinnedStyle.Copy().MaxWidth(m.width).Render(pinnedLine))
+	}
+
 	visibleLines := m.getVisibleLines()
 	hasNoHighlight := stringWidth(m.stringToHighlight) == 0
 	for idx, line := range visibleLines {
@@ -239,6 +273,9 @@ func (m Model) View() string {
 				lineStyle = v
 			}
 		}
+		if s, exists := m.contentIdxToStyle[contentIdx]; exists {
+			lineStyle = s
+		}
 		if isSelected {
 			lineStyle = m.SelectedContentStyle
 		}
@@ -317,6 +354,14 @@ func (m *Model) SetContent(content []string) {
 	m.fixSelection()
 }
 
+func (m *Model) SetContentStyles(contentIdxToStyle map[int]lipgloss.Style) {
+	m.contentIdxToStyle = contentIdxToStyle
+}
+
+func (m *Model) SetFullContent(contentIdxToFull map[int]string) {
+	m.contentIdxToFull = contentIdxToFull
+}
+
 // SetSelectedContentIdx sets the selectedContentIdx with bounds. Adjusts yOffset as necessary.
 func (m *Model) SetSelectedContentIdx(n int) {
 	if m.contentHeight == 0 {
@@ -337,6 +382,10 @@ func (m *Model) SetXOffset(n int) {
 	m.xOffset = max(0, min(maxXOffset, n))
 }
 
+func (m *Model) SetFrozenColumnWidth(width int) {
+	m.frozenColumnWidth = width
+}
+
 func (m *Model) SetStringToHighlight(h string) {
 	m.stringToHighlight = h
 }
@@ -350,6 +399,17 @@ func (m *Model) SetShowPrompt(v bool) {
 	m.showPrompt = v
 }
 
+func (m *Model) SetMaxContentHeight(maxContentHeight int) {
+	m.maxContentHeight = maxContentHeight
+	m.updateContentHeight()
+	m.fixViewForSelection()
+}
+
+func (m *Model) SetPinningEnabled(pinningEnabled bool) {
+	m.pinningEnabled = pinningEnabled
+	m.updateContentHeight()
+}
+
 func (m Model) SelectedContentIdx() int {
 	return m.selectedContentIdx
 }
@@ -398,19 +458,23 @@ func (m *Model) updateWrappedContent() {
 
 func (m *Model) updateForHeaderAndContent() {
 	m.updateMaxLineLength()
+	// content may have narrowed, e.g. when filtered
+	m.SetXOffset(m.xOffset)
 	m.updateContentHeight()
 	m.fixViewForSelection()
 }
 
 func (m *Model) updateForWrapText() {
-	m.updateContentHeight()
 	m.updateWrappedContent()
 	m.updateMaxLineLength()
 	m.SetXOffset(0)
+	// the footer depends on whether lines overflow, so content height is updated after maxLineLength
+	m.updateContentHeight()
 	m.fixViewForSelection()
 }
 
 func (m *Model) updateMaxLineLength() {
+	m.maxLineLength = 0
 	for _, line := range append(m.getHeader(), m.getContent()...) {
 		if lineLength := stringWidth(strings.TrimRight(line, " ")); lineLength > m.maxLineLength {
 			m.maxLineLength = lineLength
@@ -456,7 +520,10 @@ func (m *Model) fixSelection() {
 
 func (m *Model) updateContentHeight() {
 	_, footerHeight := m.getFooter()
-	contentHeight := m.height - len(m.getHeader()) - footerHeight
+	contentHeight := m.height - len(m.getHeader()) - m.pinnedHeight() - footerHeight
+	if m.maxContentHeight > 0 {
+		contentHeight = min(contentHeight, m.maxContentHeight)
+	}
 	m.contentHeight = max(0, contentHeight)
 }
 
@@ -499,6 +566,64 @@ func (m *Model) viewRight(n int) {
 	m.SetXOffset(m.xOffset + n)
 }
 
+// togglePin pins the selected line, or unpins it if already pinned
+func (m *Model) togglePin() {
+	if !m.selectionEnabled || len(m.content) == 0 {
+		return
+	}
+	selectedLine := m.content[m.selectedContentIdx]
+	for idx, line := range m.pinnedLines {
+		if line == selectedLine {
+			m.pinnedLines = append(m.pinnedLines[:idx], m.pinnedLines[idx+1:]...)
+			m.pinnedLineIdx = max(0, min(m.pinnedLineIdx, len(m.pinnedLines)-1))
+			m.updateContentHeight()
+			m.fixViewForSelection()
+			return
+		}
+	}
+	m.pinnedLines = append(m.pinnedLines, selectedLine)
+	m.pinnedLineIdx = len(m.pinnedLines) - 1
+	m.updateContentHeight()
+	m.fixViewForSelection()
+}
+
+// goToPin selects the shown pinned line, or cycles to the next pin if it is already selected
+func (m *Model) goToPin() {
+	if !m.selectionEnabled || len(m.pinnedLines) == 0 {
+		return
+	}
+	if len(m.content) > 0 && m.content[m.selectedContentIdx] == m.pinnedLines[m.pinnedLineIdx] {
+		m.pinnedLineIdx = (m.pinnedLineIdx + 1) % len(m.pinnedLines)
+	}
+	for idx, line := range m.content {
+		if line == m.pinnedLines[m.pinnedLineIdx] {
+			m.SetSelectedContentIdx(idx)
+			return
+		}
+	}
+	m.setToast("Pinned line not in view, e.g. filtered out", style.ErrorToast)
+}
+
+func (m Model) getPinnedLine() (string, bool) {
+	if !m.pinningEnabled || len(m.pinnedLines) == 0 {
+		return "", false
+	}
+	prefix := fmt.Sprintf("pin %d/%d: ", m.pinnedLineIdx+1, len(m.pinnedLines))
+	return prefix + strings.TrimSpace(m.pinnedLines[m.pinnedLineIdx]), true
+}
+
+func (m Model) pinnedHeight() int {
+	if _, ok := m.getPinnedLine(); ok {
+		return 1
+	}
+	return 0
+}
+
+func (m *Model) setToast(message string, toastStyle lipgloss.Style) {
+	m.toast = toast.New(message)
+	m.toast.MessageStyle = toastStyle.Copy().Width(m.width)
+}
+
 func (m *Model) updateSaveDialogPlaceholder() {
 	padding := m.width - stringWidth(constants.SaveDialogPlaceholder) - stringWidth(m.saveDialog.Prompt)
 	padding = max(0, padding)
@@ -557,11 +682,21 @@ func (m Model) getVisibleLines() []string {
 }
 
 func (m Model) getVisiblePartOfLine(line string) string {
+	if frozen := m.getFrozenColumnWidth(); frozen > 0 {
+		frozenEnd := min(stringWidth(line), frozen)
+		frozenPart := line[:frozenEnd] + strings.Repeat(" ", frozen-frozenEnd)
+		return frozenPart + m.getScrolledPartOfLine(line[frozenEnd:], m.width-frozen)
+	}
+	return m.getScrolledPartOfLine(line, m.width)
+}
+
+// getScrolledPartOfLine returns width columns of line from xOffset, marking cut off text on either side
+func (m Model) getScrolledPartOfLine(line string, width int) string {
 	rightTrimmedLineLength := stringWidth(strings.TrimRight(line, " "))
-	end := min(stringWidth(line), m.xOffset+m.width)
+	end := min(stringWidth(line), m.xOffset+width)
 	start := min(end, m.xOffset)
 	line = line[start:end]
-	if m.xOffset+m.width < rightTrimmedLineLength {
+	if m.xOffset+width < rightTrimmedLineLength {
 		truncate := max(0, stringWidth(line)-lenLineContinuationIndicator)
 		line = line[:truncate] + lineContinuationIndicator
 	}
@@ -571,6 +706,15 @@ func (m Model) getVisiblePartOfLine(line string) string {
 	return line
 }
 
+// getFrozenColumnWidth is the width kept in place when scrolled right. Nothing is frozen if that would leave less than
+// half the viewport to scroll.
+func (m Model) getFrozenColumnWidth() int {
+	if m.wrapText || m.xOffset == 0 || m.frozenColumnWidth*2 > m.width {
+		return 0
+	}
+	return m.frozenColumnWidth
+}
+
 func (m Model) getContentIdx(wrappedContentIdx int) int {
 	if !m.wrapText {
 		return wrappedContentIdx
@@ -634,9 +778,22 @@ func (m Model) getFooter() (string, int) {
 		denominator = totalNumLines
 	}
 
+	var footerParts []string
 	if totalNumLines >= m.height-len(m.getHeader()) {
 		percentScrolled := percent(numerator, denominator)
-		footerString := fmt.Sprintf("%d%% (%d/%d)", percentScrolled, numerator, denominator)
+		footerParts = append(footerParts, fmt.Sprintf("%d%% (%d/%d)", percentScrolled, numerator, denominator))
+	}
+	if !m.wrapText && m.maxLineLength > m.width {
+		// the column range of the scrolled part of lines, so it's visible that there's more to the right or left
+		start := m.xOffset + 1
+		if m.xOffset > 0 {
+			start += m.getFrozenColumnWidth()
+		}
+		end := min(m.maxLineLength, m.xOffset+m.width)
+		footerParts = append(footerParts, fmt.Sprintf("cols %d-%d/%d", start, end, m.maxLineLength))
+	}
+	if len(footerParts) > 0 {
+		footerString := strings.Join(footerParts, "  ")
 		renderedFooterString := m.FooterStyle.Copy().MaxWidth(m.width).Render(footerString)
 		footerHeight := lipgloss.Height(renderedFooterString)
 		return renderedFooterString, footerHeight
@@ -647,7 +804,13 @@ func (m Model) getFooter() (string, int) {
 func (m Model) getSaveCommand() tea.Cmd {
 	return func() tea.Msg {
 		var content string
-		for _, line := range append(m.getHeader(), m.content...) {
+		for _, line := range m.getHeader() {
+			content += strings.TrimRight(line, " ") + "\n"
+		}
+		for idx, line := range m.content {
+			if full, exists := m.contentIdxToFull[idx]; exists {
+				line = full
+			}
 			content += strings.TrimRight(line, " ") + "\n"
 		}
 
@@ -16,6 +16,22 @@ var LogoString = strings.Join([]string{
 
 const ToastDuration = time.Second * 5
 
+const InitialConnectionRetryDelay = time.Second
+
+const MaxIdleConnsPerHost = 10
+
+const MaxConnectionRetryDelay = time.Second * 30
+
+const PreflightTimeout = time.Second * 5
+
+const DisconnectCheckInterval = time.Second
+
+const DimCheckInterval = time.Second
+
+const WatchCheckInterval = time.Second * 5
+
+const RollingRestartDelay = time.Second * 5
+
 const SaveDialogPlaceholder = "Output file name (path optional)"
 
 const ExecWebSocketClosed = "> connection closed <"
@@ -33,6 +49,45 @@ var AllocationsViewportConditionalStyle = JobsViewportConditionalStyle
 
 const DefaultPageInput = "/bin/sh"
 
+const FilterHistorySize = 20
+
+// DefaultLogRedactions are regexes of secrets replaced in logs, unless overridden
+var DefaultLogRedactions = []string{
+	`(?i)bearer\s+[a-z0-9\-._~+/]+=*`,
+	`(?i)(password|passwd|secret|token)=\S+`,
+}
+
+const LogRedactionReplacement = "***"
+
+const SearchDebounceDuration = time.Millisecond * 300
+
+const SearchResultsPerCategory = 10
+
+const EvaluationPollInterval = time.Second
+
+const RecentEvaluationsCount = 10
+
+const ErrorHistoryCount = 50
+
+const RawResponseCount = 10
+
+// RawResponseMaxBytes caps how much of each response is kept, as streams like events don't end
+const RawResponseMaxBytes = 1 << 20
+
+// EventsCopyMaxBytes caps how much of the events shown are copied at once, as clipboards and terminals can't take
+// unlimited content
+const EventsCopyMaxBytes = 1 << 20
+
+// EventsPauseBufferMaxCount caps how many events are buffered while paused, after which the oldest are dropped
+const EventsPauseBufferMaxCount = 10000
+
+// TruncatedLogLineMarker ends log lines cut short for display, as rendering a multi-megabyte line freezes the ui
+const TruncatedLogLineMarker = " [truncated]"
+
+const TopJobsCount = 10
+
+const TopBarWidth = 30
+
 const DefaultEventJQQuery = `.Events[] | {
 	"1:Index": .Index,
 	"2:Topic": .Topic,
@@ -158,6 +158,13 @@ func JsonEncodedTokenArray(s string) (string, error) {
 	return string(tokensJson), nil
 }
 
+func Redact(str string, patterns []*regexp.Regexp, replacement string) string {
+	for _, p := range patterns {
+		str = p.ReplaceAllString(str, replacement)
+	}
+	return str
+}
+
 func StripANSI(str string) string {
 	return ansiRe.ReplaceAllString(str, "")
 }
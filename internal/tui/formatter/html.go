@@ -0,0 +1,194 @@
+package formatter
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var sgrRe = regexp.MustCompile("\x1b\\[([0-9;]*)m")
+
+// ansiColors are the standard and bright colors of the 16 color palette
+var ansiColors = []string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+type sgrState struct {
+	fg, bg                                 string
+	bold, faint, italic, underline, invert bool
+}
+
+func (s sgrState) css() string {
+	fg, bg := s.fg, s.bg
+	if s.invert {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "var(--bg)"
+		}
+		if bg == "" {
+			bg = "var(--fg)"
+		}
+	}
+	var props []string
+	if fg != "" {
+		props = append(props, "color:"+fg)
+	}
+	if bg != "" {
+		props = append(props, "background-color:"+bg)
+	}
+	if s.bold {
+		props = append(props, "font-weight:bold")
+	}
+	if s.faint {
+		props = append(props, "opacity:0.7")
+	}
+	if s.italic {
+		props = append(props, "font-style:italic")
+	}
+	if s.underline {
+		props = append(props, "text-decoration:underline")
+	}
+	return strings.Join(props, ";")
+}
+
+// ANSIToHTML converts text styled with ANSI SGR escape sequences, e.g. a rendered view, to HTML spans with inline
+// styles. Other escape sequences are removed.
+func ANSIToHTML(str string) string {
+	var b strings.Builder
+	var state sgrState
+	writeText := func(text string) {
+		text = html.EscapeString(StripANSI(text))
+		if text == "" {
+			return
+		}
+		if css := state.css(); css != "" {
+			fmt.Fprintf(&b, `<span style="%s">%s</span>`, css, text)
+		} else {
+			b.WriteString(text)
+		}
+	}
+
+	last := 0
+	for _, match := range sgrRe.FindAllStringSubmatchIndex(str, -1) {
+		writeText(str[last:match[0]])
+		state = applySGR(state, str[match[2]:match[3]])
+		last = match[1]
+	}
+	writeText(str[last:])
+	return b.String()
+}
+
+func applySGR(state sgrState, params string) sgrState {
+	if params == "" {
+		return sgrState{}
+	}
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			state = sgrState{}
+		case code == 1:
+			state.bold = true
+		case code == 2:
+			state.faint = true
+		case code == 3:
+			state.italic = true
+		case code == 4:
+			state.underline = true
+		case code == 7:
+			state.invert = true
+		case code == 22:
+			state.bold, state.faint = false, false
+		case code == 23:
+			state.italic = false
+		case code == 24:
+			state.underline = false
+		case code == 27:
+			state.invert = false
+		case code >= 30 && code <= 37:
+			state.fg = ansiColors[code-30]
+		case code >= 90 && code <= 97:
+			state.fg = ansiColors[code-90+8]
+		case code >= 40 && code <= 47:
+			state.bg = ansiColors[code-40]
+		case code >= 100 && code <= 107:
+			state.bg = ansiColors[code-100+8]
+		case code == 39:
+			state.fg = ""
+		case code == 49:
+			state.bg = ""
+		case code == 38 || code == 48:
+			color, consumed := extendedColor(codes[i+1:])
+			i += consumed
+			if code == 38 {
+				state.fg = color
+			} else {
+				state.bg = color
+			}
+		}
+	}
+	return state
+}
+
+// extendedColor parses the parameters following 38 or 48, i.e. 5;n for 256 colors or 2;r;g;b for true color
+func extendedColor(params []string) (string, int) {
+	ints := func(n int) ([]int, bool) {
+		if len(params) < n+1 {
+			return nil, false
+		}
+		var values []int
+		for _, p := range params[1 : n+1] {
+			v, err := strconv.Atoi(p)
+			if err != nil {
+				return nil, false
+			}
+			values = append(values, v)
+		}
+		return values, true
+	}
+
+	if len(params) == 0 {
+		return "", 0
+	}
+	switch params[0] {
+	case "5":
+		if v, ok := ints(1); ok {
+			return color256(v[0]), 2
+		}
+		return "", len(params)
+	case "2":
+		if v, ok := ints(3); ok {
+			return fmt.Sprintf("#%02x%02x%02x", v[0], v[1], v[2]), 4
+		}
+		return "", len(params)
+	}
+	return "", 1
+}
+
+func color256(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return ansiColors[n]
+	case n < 232:
+		n -= 16
+		level := func(c int) int {
+			if c == 0 {
+				return 0
+			}
+			return 55 + c*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		grey := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", grey, grey, grey)
+	}
+}
@@ -5,26 +5,146 @@ import (
 )
 
 type keyMap struct {
-	Back        key.Binding
-	Exec        key.Binding
-	Exit        key.Binding
-	JobEvents   key.Binding
-	AllocEvents key.Binding
-	AllEvents   key.Binding
-	Filter      key.Binding
-	Forward     key.Binding
-	Reload      key.Binding
-	StdOut      key.Binding
-	StdErr      key.Binding
-	Spec        key.Binding
-	Wrap        key.Binding
+	About        key.Binding
+	ACLPolicies  key.Binding
+	Back         key.Binding
+	CopyEvent    key.Binding
+	CopyEvents   key.Binding
+	CopyID       key.Binding
+	CopyShortID  key.Binding
+	CopyError    key.Binding
+	CopyBody     key.Binding
+	CopyValue    key.Binding
+	ClearErrors  key.Binding
+	Coverage     key.Binding
+	CopyLogPath  key.Binding
+	CopyLink     key.Binding
+	Deployment   key.Binding
+	Dispatch     key.Binding
+	Edit         key.Binding
+	Errors       key.Binding
+	Evaluations  key.Binding
+	Exec         key.Binding
+	Exit         key.Binding
+	Export       key.Binding
+	JobEvents    key.Binding
+	FromStart    key.Binding
+	MergeLogs    key.Binding
+	NextNS       key.Binding
+	PrevNS       key.Binding
+	NextJQQuery  key.Binding
+	OlderLogs    key.Binding
+	OpenInPager  key.Binding
+	PauseEvents  key.Binding
+	Placement    key.Binding
+	JobSource    key.Binding
+	Ports        key.Binding
+	Volumes      key.Binding
+	Stats        key.Binding
+	Checks       key.Binding
+	Collapse     key.Binding
+	CollapseAll  key.Binding
+	PrettyJSON   key.Binding
+	AllocEvents  key.Binding
+	AllEvents    key.Binding
+	AllTaskLogs  key.Binding
+	Filter       key.Binding
+	Forward      key.Binding
+	HideDead     key.Binding
+	JobType      key.Binding
+	HTMLSnapshot key.Binding
+	Quotas       key.Binding
+	Reload       key.Binding
+	RegexFilter  key.Binding
+	RawResponses key.Binding
+	Reevaluate   key.Binding
+	Rollout      key.Binding
+	RestartAll   key.Binding
+	ReverseOrder key.Binding
+	Search       key.Binding
+	Servers      key.Binding
+	StdOut       key.Binding
+	StdErr       key.Binding
+	Spec         key.Binding
+	StopAlloc    key.Binding
+	TaskEvents   key.Binding
+	Top          key.Binding
+	Wrap         key.Binding
 }
 
 var KeyMap = keyMap{
+	ACLPolicies: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "acl policies"),
+	),
 	Back: key.NewBinding(
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "back"),
 	),
+	Coverage: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "node coverage"),
+	),
+	CopyError: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy error"),
+	),
+	CopyBody: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy body"),
+	),
+	CopyValue: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy value"),
+	),
+	ClearErrors: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "clear"),
+	),
+	CopyEvent: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy event json"),
+	),
+	CopyID: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy id"),
+	),
+	CopyShortID: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy short id"),
+	),
+	CopyEvents: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy all shown"),
+	),
+	CopyLogPath: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "copy log path"),
+	),
+	Deployment: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "deployment"),
+	),
+	Dispatch: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "dispatch"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "edit spec"),
+	),
+	About: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "about"),
+	),
+	Errors: key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "errors"),
+	),
+	Evaluations: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "evaluations"),
+	),
 	Exec: key.NewBinding(
 		key.WithKeys("e"),
 		key.WithHelp("e", "exec"),
@@ -33,6 +153,14 @@ var KeyMap = keyMap{
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q/ctrl+c", "exit"),
 	),
+	Export: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "export"),
+	),
+	HTMLSnapshot: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "html snapshot"),
+	),
 	JobEvents: key.NewBinding(
 		key.WithKeys("v"),
 		key.WithHelp("v", "events"),
@@ -41,6 +169,10 @@ var KeyMap = keyMap{
 		key.WithKeys("v"),
 		key.WithHelp("v", "events"),
 	),
+	AllTaskLogs: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "all tasks"),
+	),
 	AllEvents: key.NewBinding(
 		key.WithKeys("V"),
 		key.WithHelp("V", "all events"),
@@ -53,10 +185,127 @@ var KeyMap = keyMap{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "enter"),
 	),
+	FromStart: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "from start"),
+	),
+	MergeLogs: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "merge allocs"),
+	),
+	RegexFilter: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "regex filter"),
+	),
+	NextJQQuery: key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "next query"),
+	),
+	OlderLogs: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "load older"),
+	),
+	OpenInPager: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "open in pager"),
+	),
+	PauseEvents: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "pause"),
+	),
+	Ports: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "ports"),
+	),
+	Volumes: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "volumes"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "stats"),
+	),
+	Checks: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "checks"),
+	),
+	JobSource: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "toggle source"),
+	),
+	CopyLink: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "copy link"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "collapse/expand"),
+	),
+	CollapseAll: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "collapse all"),
+	),
+	HideDead: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "hide dead"),
+	),
+	JobType: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "cycle job type"),
+	),
+	NextNS: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next namespace"),
+	),
+	PrevNS: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "prev namespace"),
+	),
+	RawResponses: key.NewBinding(
+		key.WithKeys("Z"),
+		key.WithHelp("Z", "raw responses"),
+	),
+	Placement: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "placement"),
+	),
+	PrettyJSON: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "pretty json"),
+	),
+	// shares Q with NextJQQuery, as quotas are only on the jobs page and jq queries only on events pages
+	Quotas: key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "quotas"),
+	),
 	Reload: key.NewBinding(
 		key.WithKeys("r"),
 		key.WithHelp("r", "reload"),
 	),
+	Reevaluate: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "re-evaluate"),
+	),
+	Rollout: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "watch rollout"),
+	),
+	RestartAll: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "restart allocs"),
+	),
+	ReverseOrder: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "reverse time order"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "search"),
+	),
+	Servers: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "servers"),
+	),
 	StdOut: key.NewBinding(
 		key.WithKeys("o"),
 		key.WithHelp("o", "stdout"),
@@ -69,6 +318,18 @@ var KeyMap = keyMap{
 		key.WithKeys("p"),
 		key.WithHelp("p", "spec"),
 	),
+	StopAlloc: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "stop alloc"),
+	),
+	TaskEvents: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "task events"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "top"),
+	),
 	Wrap: key.NewBinding(
 		key.WithKeys("ctrl+w"),
 		key.WithHelp("ctrl+w", "toggle wrap"),
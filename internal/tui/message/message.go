@@ -9,3 +9,19 @@ type PageInputReceivedMsg struct {
 }
 
 type CleanupCompleteMsg struct{}
+
+// FilterHistoryChangedMsg is sent when a page's filter history changes, e.g. to persist it
+type FilterHistoryChangedMsg struct {
+	History []string
+}
+
+// CopyMsg asks the app to copy Text to the clipboard, reporting the outcome as a toast naming Description
+type CopyMsg struct {
+	Text, Description string
+}
+
+// ToastMsg shows Message on the current page, or Err as an error if it is set
+type ToastMsg struct {
+	Message string
+	Err     error
+}
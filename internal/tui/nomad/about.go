@@ -0,0 +1,59 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"strings"
+)
+
+// AboutInfo is what wander already knows about itself and its connection
+type AboutInfo struct {
+	Version, Commit   string
+	Addr              string
+	ServerVersion     string
+	Namespace, Region string
+	Profile           string
+}
+
+// FetchAbout lists build and connection info for screenshots and bug reports, with the token's accessor and name rather
+// than the token itself. Each row's key is its value, to copy.
+func FetchAbout(client api.Client, info AboutInfo) tea.Cmd {
+	return func() tea.Msg {
+		var token string
+		if self, _, err := client.ACLTokens().Self(nil); err == nil {
+			token = fmt.Sprintf("%s (%s, %s", self.AccessorID, orDash(self.Name), self.Type)
+			if len(self.Policies) > 0 {
+				token += ", policies " + strings.Join(self.Policies, ", ")
+			}
+			token += ")"
+		} else if strings.Contains(err.Error(), "ACL support disabled") {
+			token = "none, ACLs are disabled"
+		} else if IsAuthError(err) {
+			token = "anonymous or not found"
+		} else {
+			token = fmt.Sprintf("unknown: %v", err)
+		}
+
+		serverVersion := info.ServerVersion
+		if serverVersion == "" {
+			serverVersion = "unknown, requires agent:read"
+		}
+		items := [][2]string{
+			{"wander version", orDash(info.Version)},
+			{"wander commit", orDash(info.Commit)},
+			{"nomad address", info.Addr},
+			{"nomad version", serverVersion},
+			{"namespace", orDash(info.Namespace)},
+			{"region", orDash(info.Region)},
+			{"profile", orDash(info.Profile)},
+			{"token", token},
+		}
+		var rows []page.Row
+		for _, item := range items {
+			rows = append(rows, page.Row{Key: item[1], Row: fmt.Sprintf("%-16s%s", item[0], item[1])})
+		}
+		return PageLoadedMsg{Page: AboutPage, TableHeader: []string{}, AllPageRows: rows}
+	}
+}
@@ -0,0 +1,74 @@
+package nomad
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strings"
+)
+
+type ACLAccessCheckedMsg struct {
+	CanRead bool
+}
+
+// CheckACLAccess checks whether the token can list ACL policies, which requires a management token. ACL roles are
+// not available in the Nomad API version wander is built against, so only policies are browsable.
+func CheckACLAccess(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.ACLPolicies().List(nil)
+		return ACLAccessCheckedMsg{CanRead: err == nil}
+	}
+}
+
+func FetchACLPolicies(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		policies, _, err := client.ACLPolicies().List(nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		sort.Slice(policies, func(x, y int) bool {
+			return policies[x].Name < policies[y].Name
+		})
+
+		var policyRows [][]string
+		var keys []string
+		for _, p := range policies {
+			policyRows = append(policyRows, []string{p.Name, p.Description})
+			keys = append(keys, p.Name)
+		}
+
+		columns := []string{"Name", "Description"}
+		table := formatter.GetRenderedTableAsString(columns, policyRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		}
+
+		return PageLoadedMsg{Page: ACLPoliciesPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func FetchACLPolicy(client api.Client, name string) tea.Cmd {
+	return func() tea.Msg {
+		policy, _, err := client.ACLPolicies().Info(name, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var policyPageData []page.Row
+		for _, row := range strings.Split(strings.TrimSpace(policy.Rules), "\n") {
+			policyPageData = append(policyPageData, page.Row{Key: "", Row: row})
+		}
+
+		return PageLoadedMsg{
+			Page:        ACLPolicyPage,
+			TableHeader: []string{},
+			AllPageRows: policyPageData,
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"time"
+)
+
+type AllocationStoppedMsg struct {
+	AllocID, EvalID string
+}
+
+type JobEvaluatedMsg struct {
+	JobID, EvalID string
+}
+
+// StopAllocation stops a single allocation, which causes Nomad to reschedule it
+func StopAllocation(client api.Client, alloc api.Allocation, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := client.Allocations().Stop(&alloc, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return AllocationStoppedMsg{AllocID: alloc.ID, EvalID: resp.EvalID}
+	}
+}
+
+// ForceEvaluateJob creates a new evaluation for the job, e.g. to retry stuck placements
+func ForceEvaluateJob(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		evalID, _, err := client.Jobs().ForceEvaluate(jobID, &api.WriteOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return JobEvaluatedMsg{JobID: jobID, EvalID: evalID}
+	}
+}
+
+// RollingRestartMsg is the progress of restarting a job's running allocations one at a time. Restarted of AllocIDs
+// have been restarted so far.
+type RollingRestartMsg struct {
+	JobID, JobNamespace string
+	AllocIDs            []string
+	Restarted           int
+}
+
+// StartRollingRestart lists the job's running allocations to restart with RestartNextAllocation
+func StartRollingRestart(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		allocs, _, err := client.Jobs().Allocations(jobID, false, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		var allocIDs []string
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == "running" {
+				allocIDs = append(allocIDs, alloc.ID)
+			}
+		}
+		if len(allocIDs) == 0 {
+			return message.ToastMsg{Err: fmt.Errorf("%s has no running allocations to restart", jobID)}
+		}
+		return RollingRestartMsg{JobID: jobID, JobNamespace: jobNamespace, AllocIDs: allocIDs}
+	}
+}
+
+// RestartNextAllocation restarts the next allocation of a rolling restart, then waits before reporting progress so
+// allocations aren't all down at once. It stops at the first failure, reporting how many were restarted.
+func RestartNextAllocation(client api.Client, progress RollingRestartMsg) tea.Cmd {
+	return func() tea.Msg {
+		allocID := progress.AllocIDs[progress.Restarted]
+		failed := func(err error) tea.Msg {
+			return message.ToastMsg{Err: fmt.Errorf(
+				"restarted %d of %d allocations of %s, then restarting %s failed: %w",
+				progress.Restarted, len(progress.AllocIDs), progress.JobID, formatter.ShortAllocID(allocID), err,
+			)}
+		}
+
+		q := &api.QueryOptions{Namespace: progress.JobNamespace}
+		alloc, _, err := client.Allocations().Info(allocID, q)
+		if err != nil {
+			return failed(err)
+		}
+		if err = client.Allocations().Restart(alloc, "", q); err != nil {
+			return failed(err)
+		}
+		progress.Restarted += 1
+		if progress.Restarted < len(progress.AllocIDs) {
+			time.Sleep(constants.RollingRestartDelay)
+		}
+		return progress
+	}
+}
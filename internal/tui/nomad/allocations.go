@@ -2,11 +2,14 @@ package nomad
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/nomad/api"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
 	"github.com/robinovitch61/wander/internal/tui/formatter"
 	"github.com/robinovitch61/wander/internal/tui/message"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,16 +22,29 @@ const keySeparator = "|【=◈︿◈=】|"
 type allocationRowEntry struct {
 	FullAllocationAsJSON                 string
 	ID, TaskGroup, Name, TaskName, State string
+	Datacenter                           string
 	StartedAt, FinishedAt                time.Time
 }
 
-func FetchAllocations(client api.Client, jobID, jobNamespace string) tea.Cmd {
+// FetchAllocations lists the tasks of the job's allocations. If showDatacenters, nodes are listed to show the datacenter
+// of each allocation's node, or "-" if the token can't read nodes. Tasks of collapsedGroups are summarized in a row per
+// group above the rest instead of listed, which can be selected to expand the group.
+func FetchAllocations(client api.Client, jobID, jobNamespace string, showDatacenters bool, icons IconSet, collapsedGroups map[string]bool) tea.Cmd {
 	return func() tea.Msg {
 		allocs, _, err := client.Jobs().Allocations(jobID, true, &api.QueryOptions{Namespace: jobNamespace})
 		if err != nil {
 			return message.ErrMsg{Err: err}
 		}
 
+		datacenterByNode := make(map[string]string)
+		if showDatacenters {
+			if nodes, _, err := client.Nodes().List(nil); err == nil {
+				for _, node := range nodes {
+					datacenterByNode[node.ID] = node.Datacenter
+				}
+			}
+		}
+
 		var allocationRowEntries []allocationRowEntry
 		for _, alloc := range allocs {
 			allocAsJSON, err := json.Marshal(alloc)
@@ -44,6 +60,7 @@ func FetchAllocations(client api.Client, jobID, jobNamespace string) tea.Cmd {
 					Name:                 alloc.Name,
 					TaskName:             taskName,
 					State:                task.State,
+					Datacenter:           datacenterByNode[alloc.NodeID],
 					StartedAt:            task.StartedAt.UTC(),
 					FinishedAt:           task.FinishedAt.UTC(),
 				})
@@ -68,12 +85,19 @@ func FetchAllocations(client api.Client, jobID, jobNamespace string) tea.Cmd {
 			return firstTask.TaskName < secondTask.TaskName
 		})
 
-		tableHeader, allPageData := allocationsAsTable(allocationRowEntries)
+		var shown []allocationRowEntry
+		for _, e := range allocationRowEntries {
+			if !collapsedGroups[e.TaskGroup] {
+				shown = append(shown, e)
+			}
+		}
+		tableHeader, allPageData := allocationsAsTable(shown, showDatacenters, icons)
+		allPageData = append(collapsedGroupRows(allocationRowEntries, collapsedGroups), allPageData...)
 		return PageLoadedMsg{Page: AllocationsPage, TableHeader: tableHeader, AllPageRows: allPageData}
 	}
 }
 
-func allocationsAsTable(allocations []allocationRowEntry) ([]string, []page.Row) {
+func allocationsAsTable(allocations []allocationRowEntry, showDatacenters bool, icons IconSet) ([]string, []page.Row) {
 	var allocationResponseRows [][]string
 	var keys []string
 	for _, row := range allocations {
@@ -81,30 +105,115 @@ func allocationsAsTable(allocations []allocationRowEntry) ([]string, []page.Row)
 		if row.State == "running" {
 			uptime = formatter.FormatTimeNsSinceNow(row.StartedAt.UnixNano())
 		}
-		allocationResponseRows = append(allocationResponseRows, []string{
+		allocationResponseRow := []string{
 			formatter.ShortAllocID(row.ID),
 			row.TaskGroup,
 			row.Name,
 			row.TaskName,
-			row.State,
+			withStatusIcon(row.State, icons),
+		}
+		if showDatacenters {
+			allocationResponseRow = append(allocationResponseRow, orDash(row.Datacenter))
+		}
+		allocationResponseRow = append(allocationResponseRow,
 			formatter.FormatTime(row.StartedAt),
 			formatter.FormatTime(row.FinishedAt),
 			uptime,
-		})
+		)
+		allocationResponseRows = append(allocationResponseRows, allocationResponseRow)
 		keys = append(keys, toAllocationsKey(row))
 	}
 
-	columns := []string{"Alloc ID", "Task Group", "Alloc Name", "Task Name", "State", "Started", "Finished", "Uptime"}
+	columns := []string{"Alloc ID", "Task Group", "Alloc Name", "Task Name", "State"}
+	if showDatacenters {
+		columns = append(columns, "Datacenter")
+	}
+	columns = append(columns, "Started", "Finished", "Uptime")
 	table := formatter.GetRenderedTableAsString(columns, allocationResponseRows)
 
 	var rows []page.Row
 	for idx, row := range table.ContentRows {
-		rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		rows = append(rows, page.Row{Key: keys[idx], Row: row, Fields: allocationFilterFields(allocations[idx], showDatacenters)})
 	}
 
 	return table.HeaderRows, rows
 }
 
+func allocationFilterFields(allocation allocationRowEntry, showDatacenters bool) map[string]string {
+	fields := map[string]string{
+		"id":         allocation.ID,
+		"task_group": allocation.TaskGroup,
+		"name":       allocation.Name,
+		"task":       allocation.TaskName,
+		"state":      allocation.State,
+		"started":    formatter.FormatTime(allocation.StartedAt),
+		"finished":   formatter.FormatTime(allocation.FinishedAt),
+	}
+	if showDatacenters {
+		fields["datacenter"] = allocation.Datacenter
+	}
+	return fields
+}
+
+// collapsedGroupRows has a row for each collapsed task group with its allocation count and task states
+func collapsedGroupRows(allocations []allocationRowEntry, collapsedGroups map[string]bool) []page.Row {
+	allocIDs := make(map[string]map[string]bool)
+	states := make(map[string]map[string]int)
+	for _, a := range allocations {
+		if !collapsedGroups[a.TaskGroup] {
+			continue
+		}
+		if allocIDs[a.TaskGroup] == nil {
+			allocIDs[a.TaskGroup], states[a.TaskGroup] = make(map[string]bool), make(map[string]int)
+		}
+		allocIDs[a.TaskGroup][a.ID] = true
+		states[a.TaskGroup][a.State]++
+	}
+
+	var groups []string
+	for g := range allocIDs {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	var rows []page.Row
+	for _, g := range groups {
+		var stateNames []string
+		for s := range states[g] {
+			stateNames = append(stateNames, s)
+		}
+		sort.Strings(stateNames)
+		var counts []string
+		for _, s := range stateNames {
+			counts = append(counts, fmt.Sprintf("%s %d", s, states[g][s]))
+		}
+		rows = append(rows, page.Row{
+			Key:    collapsedGroupKeyPrefix + g,
+			Row:    fmt.Sprintf("▸ %s (collapsed): %d allocations, tasks %s", g, len(allocIDs[g]), strings.Join(counts, ", ")),
+			Fields: map[string]string{"task_group": g},
+		})
+	}
+	return rows
+}
+
+// collapsedGroupKeyPrefix starts the keys of collapsed task group rows, which can't be mistaken for allocation keys as
+// those start with JSON
+const collapsedGroupKeyPrefix = "collapsed" + keySeparator
+
+// CollapsedTaskGroupFromKey returns the task group of a collapsed task group row's key, and false for any other row
+func CollapsedTaskGroupFromKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, collapsedGroupKeyPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(key, collapsedGroupKeyPrefix), true
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func toAllocationsKey(allocationRowEntry allocationRowEntry) string {
 	isRunning := "false"
 	if allocationRowEntry.State == "running" {
@@ -113,6 +222,57 @@ func toAllocationsKey(allocationRowEntry allocationRowEntry) string {
 	return allocationRowEntry.FullAllocationAsJSON + keySeparator + allocationRowEntry.TaskName + keySeparator + isRunning
 }
 
+// FirstTaskName returns the alphabetically first task name in the allocation
+func FirstTaskName(alloc api.Allocation) string {
+	var taskNames []string
+	for taskName := range alloc.TaskStates {
+		taskNames = append(taskNames, taskName)
+	}
+	if len(taskNames) == 0 {
+		return ""
+	}
+	sort.Strings(taskNames)
+	return taskNames[0]
+}
+
+// DefaultTaskName returns the first task in the allocation matching the earliest of the preferred patterns, e.g. to skip
+// sidecars, falling back to the alphabetically first task
+func DefaultTaskName(alloc api.Allocation, preferred []*regexp.Regexp) string {
+	var taskNames []string
+	for taskName := range alloc.TaskStates {
+		taskNames = append(taskNames, taskName)
+	}
+	sort.Strings(taskNames)
+	for _, re := range preferred {
+		for _, taskName := range taskNames {
+			if re.MatchString(taskName) {
+				return taskName
+			}
+		}
+	}
+	return FirstTaskName(alloc)
+}
+
+// PreferredTaskRowIdx returns the index of the first allocations page row of a task matching the earliest of the
+// preferred patterns, or -1 if none match
+func PreferredTaskRowIdx(rows []page.Row, preferred []*regexp.Regexp) int {
+	taskNames := make([]string, len(rows))
+	for idx, row := range rows {
+		// e.g. collapsed task group rows have no allocation
+		if allocInfo, err := AllocationInfoFromKey(row.Key); err == nil {
+			taskNames[idx] = allocInfo.TaskName
+		}
+	}
+	for _, re := range preferred {
+		for idx, taskName := range taskNames {
+			if taskName != "" && re.MatchString(taskName) {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
 type AllocationInfo struct {
 	Alloc    api.Allocation
 	TaskName string
@@ -121,6 +281,9 @@ type AllocationInfo struct {
 
 func AllocationInfoFromKey(key string) (AllocationInfo, error) {
 	split := strings.Split(key, keySeparator)
+	if len(split) != 3 {
+		return AllocationInfo{}, errors.New("not an allocation")
+	}
 	running, err := strconv.ParseBool(split[2])
 	if err != nil {
 		return AllocationInfo{}, err
@@ -132,3 +295,29 @@ func AllocationInfoFromKey(key string) (AllocationInfo, error) {
 	}
 	return AllocationInfo{Alloc: alloc, TaskName: split[1], Running: running}, nil
 }
+
+// LastAllocationError describes the most recent failing event of the task in a failed or lost allocation
+func LastAllocationError(alloc api.Allocation, taskName string) (string, bool) {
+	taskState, exists := alloc.TaskStates[taskName]
+	if !exists || (alloc.ClientStatus != "failed" && alloc.ClientStatus != "lost" && !taskState.Failed) {
+		return "", false
+	}
+
+	for i := len(taskState.Events) - 1; i >= 0; i-- {
+		e := taskState.Events[i]
+		if !e.FailsTask && e.ExitCode == 0 && e.DriverError == "" {
+			continue
+		}
+		message := e.DisplayMessage
+		for _, m := range []string{e.DriverError, e.Message, e.KillError, e.SetupError} {
+			if message == "" {
+				message = m
+			}
+		}
+		return fmt.Sprintf(
+			"Last error in %s at %s: %s (exit code %d): %s",
+			taskName, formatter.FormatTimeNs(e.Time), e.Type, e.ExitCode, message,
+		), true
+	}
+	return fmt.Sprintf("%s %s, no failing task event found", taskName, alloc.ClientStatus), true
+}
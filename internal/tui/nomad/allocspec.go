@@ -2,13 +2,20 @@ package nomad
 
 import (
 	"encoding/json"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/nomad/api"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
 	"github.com/robinovitch61/wander/internal/tui/formatter"
 	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strings"
+	"time"
 )
 
+// rescheduleHistoryCount is how many of an allocation's most recent reschedules are listed above its spec
+const rescheduleHistoryCount = 5
+
 func FetchAllocSpec(client api.Client, allocID string) tea.Cmd {
 	return func() tea.Msg {
 		alloc, _, err := client.Allocations().Info(allocID, nil)
@@ -29,8 +36,64 @@ func FetchAllocSpec(client api.Client, allocID string) tea.Cmd {
 
 		return PageLoadedMsg{
 			Page:        AllocSpecPage,
-			TableHeader: []string{},
+			TableHeader: restartSummary(client, alloc),
 			AllPageRows: allocSpecPageData,
 		}
 	}
 }
+
+// restartSummary shows whether an allocation is stuck restarting or rescheduling: its tasks' restarts, the allocations
+// it replaced, and when it will next be rescheduled
+func restartSummary(client api.Client, alloc *api.Allocation) []string {
+	var taskNames []string
+	for name := range alloc.TaskStates {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+	var restarts []string
+	for _, name := range taskNames {
+		state := alloc.TaskStates[name]
+		restart := fmt.Sprintf("%s %d", name, state.Restarts)
+		if state.Restarts > 0 {
+			restart += fmt.Sprintf(" (last %s)", formatter.FormatTime(state.LastRestart))
+		}
+		restarts = append(restarts, restart)
+	}
+	summary := []string{"Restarts: " + strings.Join(restarts, ", ")}
+
+	var events []*api.RescheduleEvent
+	if alloc.RescheduleTracker != nil {
+		events = alloc.RescheduleTracker.Events
+	}
+	reschedules := fmt.Sprintf("Reschedules: %d", len(events))
+	if tg := alloc.GetTaskGroup(); tg != nil && tg.ReschedulePolicy != nil {
+		if policy := tg.ReschedulePolicy; derefBool(policy.Unlimited) {
+			reschedules += ", unlimited"
+		} else if policy.Attempts != nil && policy.Interval != nil {
+			attempted, available := alloc.RescheduleInfo(time.Now())
+			reschedules += fmt.Sprintf(", %d of %d attempts used in the last %s", attempted, available, *policy.Interval)
+		}
+	}
+	switch {
+	case alloc.NextAllocation != "":
+		reschedules += fmt.Sprintf(", replaced by %s", formatter.ShortAllocID(alloc.NextAllocation))
+	case alloc.FollowupEvalID != "":
+		// the follow up evaluation is blocked until the reschedule delay has passed
+		eval, _, err := client.Evaluations().Info(alloc.FollowupEvalID, nil)
+		if err == nil && !eval.WaitUntil.IsZero() {
+			reschedules += fmt.Sprintf(", next reschedule eligible at %s", formatter.FormatTime(eval.WaitUntil))
+		}
+	}
+	summary = append(summary, reschedules)
+
+	for i := len(events) - 1; i >= 0 && i >= len(events)-rescheduleHistoryCount; i-- {
+		e := events[i]
+		summary = append(summary, fmt.Sprintf(
+			"  rescheduled from %s on node %s at %s", formatter.ShortAllocID(e.PrevAllocID), formatter.ShortAllocID(e.PrevNodeID), formatter.FormatTimeNs(e.RescheduleTime),
+		))
+	}
+	if len(events) > rescheduleHistoryCount {
+		summary = append(summary, fmt.Sprintf("  and %d earlier, see RescheduleTracker below", len(events)-rescheduleHistoryCount))
+	}
+	return summary
+}
@@ -0,0 +1,24 @@
+package nomad
+
+import (
+	"os"
+	"strings"
+)
+
+// ReadTokenFile reads a token from a file, e.g. one kept up to date by a token rotation agent
+func ReadTokenFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// IsAuthError is true if the Nomad API rejected the request's token, e.g. as it expired or was rotated
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	s := err.Error()
+	return strings.Contains(s, "403") || strings.Contains(s, "Permission denied") || strings.Contains(s, "ACL token not found")
+}
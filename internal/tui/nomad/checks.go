@@ -0,0 +1,123 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"net/url"
+	"strings"
+)
+
+// checkResult is the latest result of a Nomad service check, reported by Nomad 1.4 and later
+type checkResult struct {
+	Service, Check, Status, Output string
+}
+
+type serviceCheck struct {
+	Service, Provider, Task, Check, Status, Output string
+}
+
+// FetchAllocChecks lists the services the allocation's group and tasks register and the status of their checks. Nomad
+// only knows the status of checks of services it registers itself, so those registered in Consul are marked as such.
+func FetchAllocChecks(client api.Client, allocID string) tea.Cmd {
+	return func() tea.Msg {
+		alloc, _, err := client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		var group *api.TaskGroup
+		if alloc.Job != nil {
+			for _, tg := range alloc.Job.TaskGroups {
+				if derefString(tg.Name) == alloc.TaskGroup {
+					group = tg
+					break
+				}
+			}
+		}
+		if group == nil {
+			return PageLoadedMsg{Page: AllocChecksPage, TableHeader: []string{"No services registered"}, AllPageRows: []page.Row{}}
+		}
+
+		results, resultsErr := fetchCheckResults(client, allocID)
+
+		var checks []serviceCheck
+		checks = append(checks, toServiceChecks("", group.Services, results, resultsErr)...)
+		for _, task := range group.Tasks {
+			checks = append(checks, toServiceChecks(task.Name, task.Services, results, resultsErr)...)
+		}
+		if len(checks) == 0 {
+			return PageLoadedMsg{Page: AllocChecksPage, TableHeader: []string{"No services registered"}, AllPageRows: []page.Row{}}
+		}
+
+		var checkRows [][]string
+		for _, c := range checks {
+			checkRows = append(checkRows, []string{c.Service, c.Provider, orDash(c.Task), c.Check, c.Status, c.Output})
+		}
+		columns := []string{"Service", "Provider", "Task", "Check", "Status", "Output"}
+		table := formatter.GetRenderedTableAsString(columns, checkRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row, Fields: map[string]string{
+				"service":  checks[idx].Service,
+				"provider": checks[idx].Provider,
+				"check":    checks[idx].Check,
+				"status":   checks[idx].Status,
+			}})
+		}
+
+		return PageLoadedMsg{Page: AllocChecksPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func toServiceChecks(task string, services []*api.Service, results []checkResult, resultsErr error) []serviceCheck {
+	var checks []serviceCheck
+	for _, s := range services {
+		if s == nil {
+			continue
+		}
+		provider := s.Provider
+		if provider == "" {
+			provider = "consul"
+		}
+		if len(s.Checks) == 0 {
+			checks = append(checks, serviceCheck{Service: s.Name, Provider: provider, Task: task, Check: "-", Status: "no checks"})
+			continue
+		}
+		for _, sc := range s.Checks {
+			c := serviceCheck{Service: s.Name, Provider: provider, Task: task, Check: sc.Name}
+			switch {
+			case provider != "nomad":
+				c.Status, c.Output = "-", "Checked by Consul, which Nomad doesn't report the status of"
+			case resultsErr != nil:
+				c.Status, c.Output = "-", fmt.Sprintf("Check status not available: %s", resultsErr.Error())
+			default:
+				c.Status, c.Output = "pending", ""
+				for _, r := range results {
+					if r.Service == s.Name && r.Check == sc.Name {
+						c.Status, c.Output = r.Status, strings.TrimSpace(r.Output)
+						break
+					}
+				}
+			}
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+func fetchCheckResults(client api.Client, allocID string) ([]checkResult, error) {
+	var resultsByID map[string]checkResult
+	endpoint := fmt.Sprintf("/v1/client/allocation/%s/checks", url.PathEscape(allocID))
+	if _, err := client.Raw().Query(endpoint, &resultsByID, nil); err != nil {
+		return nil, err
+	}
+	var results []checkResult
+	for _, r := range resultsByID {
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,27 @@
+package nomad
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"time"
+)
+
+type ConnectionCheckedMsg struct {
+	Err error
+}
+
+type RetryConnectionMsg struct {
+	ID int
+}
+
+// CheckConnection checks that the Nomad API is reachable. The leader endpoint requires no ACL token.
+func CheckConnection(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Status().Leader()
+		return ConnectionCheckedMsg{Err: err}
+	}
+}
+
+func RetryConnectionWithDelay(id int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg { return RetryConnectionMsg{id} })
+}
@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/style"
+	"path"
+	"sort"
+	"strconv"
+)
+
+type nodeCoverage struct {
+	ID, Name, Datacenter string
+	RunningAllocs        int
+}
+
+// FetchJobCoverage compares the ready, eligible nodes in a system job's datacenters to the nodes running it. Constraints
+// aren't evaluated, so nodes they exclude are listed as missing the job.
+func FetchJobCoverage(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		if jobType := derefString(job.Type); jobType != "system" {
+			tableHeader := []string{fmt.Sprintf("Coverage only applies to system jobs, and %s is a %s job", jobID, jobType)}
+			return PageLoadedMsg{Page: JobCoveragePage, TableHeader: tableHeader, AllPageRows: []page.Row{}}
+		}
+
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		allocs, _, err := client.Jobs().Allocations(jobID, false, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		runningByNode := make(map[string]int)
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == "running" {
+				runningByNode[alloc.NodeID] += 1
+			}
+		}
+
+		var coverage []nodeCoverage
+		for _, node := range nodes {
+			if node.Status != "ready" || node.SchedulingEligibility != "eligible" || !inDatacenters(node.Datacenter, job.Datacenters) {
+				continue
+			}
+			coverage = append(coverage, nodeCoverage{
+				ID:            node.ID,
+				Name:          node.Name,
+				Datacenter:    node.Datacenter,
+				RunningAllocs: runningByNode[node.ID],
+			})
+		}
+		sort.Slice(coverage, func(x, y int) bool {
+			// nodes missing the job first
+			if (coverage[x].RunningAllocs == 0) != (coverage[y].RunningAllocs == 0) {
+				return coverage[x].RunningAllocs == 0
+			}
+			return coverage[x].Name < coverage[y].Name
+		})
+
+		var running int
+		var nodeRows [][]string
+		for _, c := range coverage {
+			status := "missing"
+			if c.RunningAllocs > 0 {
+				status = "running"
+				running += 1
+			}
+			nodeRows = append(nodeRows, []string{
+				c.Name,
+				formatter.ShortAllocID(c.ID),
+				c.Datacenter,
+				status,
+				strconv.Itoa(c.RunningAllocs),
+			})
+		}
+		columns := []string{"Node", "ID", "Datacenter", "Status", "Running Allocs"}
+		table := formatter.GetRenderedTableAsString(columns, nodeRows)
+
+		// the summary is part of the header so it stays visible while scrolling
+		tableHeader := []string{
+			fmt.Sprintf("Running on %d of %d ready, eligible nodes in its datacenters", running, len(coverage)),
+			"Constraints aren't evaluated, so nodes they exclude show as missing",
+			"",
+		}
+		tableHeader = append(tableHeader, table.HeaderRows...)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			r := page.Row{Key: coverage[idx].ID, Row: row}
+			if coverage[idx].RunningAllocs == 0 {
+				r.Style = &style.CoverageMissing
+			}
+			rows = append(rows, r)
+		}
+
+		return PageLoadedMsg{Page: JobCoveragePage, TableHeader: tableHeader, AllPageRows: rows}
+	}
+}
+
+func inDatacenters(datacenter string, datacenters []string) bool {
+	for _, dc := range datacenters {
+		// newer Nomad versions allow globs in job datacenters
+		if matched, err := path.Match(dc, datacenter); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"time"
+)
+
+// FetchJobDeployment shows the job's latest deployment next to each task group's update strategy, and what Nomad will do
+// next if the deployment is running, e.g. auto-promote canaries once healthy
+func FetchJobDeployment(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		deployment, _, err := client.Jobs().LatestDeployment(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var lines []string
+		if deployment == nil {
+			lines = append(lines, "No deployments")
+		} else {
+			line := fmt.Sprintf("Deployment %s %s", formatter.ShortAllocID(deployment.ID), deployment.Status)
+			if deployment.StatusDescription != "" {
+				line += fmt.Sprintf(" (%s)", deployment.StatusDescription)
+			}
+			lines = append(lines, line)
+		}
+
+		for _, tg := range job.TaskGroups {
+			name := derefString(tg.Name)
+			lines = append(lines, "", fmt.Sprintf("Group %s", name))
+
+			update := tg.Update
+			if update == nil {
+				update = job.Update
+			}
+			if update == nil {
+				lines = append(lines, placementIndent+"No update strategy")
+			} else {
+				lines = append(lines, fmt.Sprintf(
+					"%sUpdate strategy: canary %d, max_parallel %d, auto_revert %t, auto_promote %t, healthy_deadline %s, progress_deadline %s",
+					placementIndent, derefInt(update.Canary), derefInt(update.MaxParallel), derefBool(update.AutoRevert),
+					derefBool(update.AutoPromote), derefDuration(update.HealthyDeadline), derefDuration(update.ProgressDeadline),
+				))
+			}
+
+			if deployment == nil {
+				continue
+			}
+			state, exists := deployment.TaskGroups[name]
+			if !exists || state == nil {
+				lines = append(lines, placementIndent+"Not part of the deployment")
+				continue
+			}
+			lines = append(lines, deploymentStateLines(deployment.Status, state, update != nil && derefBool(update.AutoPromote))...)
+		}
+
+		var rows []page.Row
+		for _, l := range lines {
+			rows = append(rows, page.Row{Row: l})
+		}
+		return PageLoadedMsg{Page: JobDeploymentPage, TableHeader: []string{}, AllPageRows: rows}
+	}
+}
+
+// deploymentStateLines describes a task group's deployment state. Whether canaries are auto-promoted comes from the
+// group's update strategy, as the deployment state doesn't carry it.
+func deploymentStateLines(status string, state *api.DeploymentState, autoPromote bool) []string {
+	lines := []string{fmt.Sprintf(
+		"%sHealth: %d desired, %d placed, %d healthy, %d unhealthy",
+		placementIndent, state.DesiredTotal, state.PlacedAllocs, state.HealthyAllocs, state.UnhealthyAllocs,
+	)}
+	if state.DesiredCanaries > 0 {
+		lines = append(lines, fmt.Sprintf(
+			"%sCanaries: %d desired, %d placed, promoted %t",
+			placementIndent, state.DesiredCanaries, len(state.PlacedCanaries), state.Promoted,
+		))
+	}
+	if status != "running" {
+		return lines
+	}
+
+	var next []string
+	if state.DesiredCanaries > 0 && !state.Promoted {
+		if autoPromote {
+			next = append(next, "auto-promote once all canaries are healthy")
+		} else {
+			next = append(next, "wait for canaries to be promoted manually")
+		}
+	}
+	if state.AutoRevert {
+		next = append(next, "auto-revert to the last stable version if the deployment fails")
+	}
+	if !state.RequireProgressBy.IsZero() {
+		next = append(next, fmt.Sprintf("fail unless an allocation becomes healthy by %s", formatter.FormatTime(state.RequireProgressBy)))
+	}
+	for _, n := range next {
+		lines = append(lines, fmt.Sprintf("%sNext: %s", placementIndent, n))
+	}
+	return lines
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func derefBool(b *bool) bool {
+	if b == nil {
+		return false
+	}
+	return *b
+}
+
+func derefDuration(d *time.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return *d
+}
@@ -0,0 +1,140 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"os"
+	"strings"
+)
+
+const noDispatchInput = "-"
+
+type ParameterizedJobFetchedMsg struct {
+	Job *api.Job
+}
+
+type JobDispatchedMsg struct {
+	DispatchedJobID, EvalID string
+}
+
+type JobDispatchFailedMsg struct {
+	Err error
+}
+
+// FetchParameterizedJob fetches the job to dispatch, erroring if it is not parameterized
+func FetchParameterizedJob(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		if job.ParameterizedJob == nil {
+			return message.ToastMsg{Err: fmt.Errorf("%s is not a parameterized job", jobID)}
+		}
+		return ParameterizedJobFetchedMsg{Job: job}
+	}
+}
+
+func LoadDispatchPage() tea.Cmd {
+	return func() tea.Msg {
+		// like exec, the dispatch input is requested before any content is shown
+		return PageLoadedMsg{Page: DispatchPage, TableHeader: []string{}, AllPageRows: []page.Row{}}
+	}
+}
+
+// DispatchInputPrefix describes the meta keys and payload the parameterized job accepts
+func DispatchInputPrefix(job *api.Job) string {
+	p := job.ParameterizedJob
+	payload := p.Payload
+	if payload == "" {
+		payload = "optional"
+	}
+	return fmt.Sprintf(
+		"Required meta: %s | Optional meta: %s | Payload: %s\nEnter meta as key=value and payload as @file, or %s for none: ",
+		joinOrNone(p.MetaRequired),
+		joinOrNone(p.MetaOptional),
+		payload,
+		noDispatchInput,
+	)
+}
+
+// DispatchInputTemplate pre-fills the required meta keys
+func DispatchInputTemplate(job *api.Job) string {
+	var template []string
+	for _, k := range job.ParameterizedJob.MetaRequired {
+		template = append(template, k+"=")
+	}
+	if len(template) == 0 {
+		return noDispatchInput
+	}
+	return strings.Join(template, " ")
+}
+
+// ParseDispatchInput parses input like `key=value other=value @payload.json`, validating it against the job's
+// parameterized config
+func ParseDispatchInput(input string, job *api.Job) (map[string]string, []byte, error) {
+	p := job.ParameterizedJob
+	allowedMeta := make(map[string]bool)
+	for _, k := range append(append([]string{}, p.MetaRequired...), p.MetaOptional...) {
+		allowedMeta[k] = true
+	}
+
+	meta := make(map[string]string)
+	var payload []byte
+	for _, token := range strings.Fields(input) {
+		switch {
+		case token == noDispatchInput:
+			continue
+		case strings.HasPrefix(token, "@"):
+			if payload != nil {
+				return nil, nil, fmt.Errorf("only one payload file allowed")
+			}
+			var err error
+			payload, err = os.ReadFile(strings.TrimPrefix(token, "@"))
+			if err != nil {
+				return nil, nil, err
+			}
+		case strings.Contains(token, "="):
+			parts := strings.SplitN(token, "=", 2)
+			if !allowedMeta[parts[0]] {
+				return nil, nil, fmt.Errorf("meta key %s not allowed by job", parts[0])
+			}
+			meta[parts[0]] = parts[1]
+		default:
+			return nil, nil, fmt.Errorf("%s is not of the form key=value or @file", token)
+		}
+	}
+
+	for _, k := range p.MetaRequired {
+		if meta[k] == "" {
+			return nil, nil, fmt.Errorf("missing required meta key %s", k)
+		}
+	}
+	if p.Payload == "required" && payload == nil {
+		return nil, nil, fmt.Errorf("payload required")
+	}
+	if p.Payload == "forbidden" && payload != nil {
+		return nil, nil, fmt.Errorf("payload forbidden")
+	}
+	return meta, payload, nil
+}
+
+func DispatchJob(client api.Client, jobID, jobNamespace string, meta map[string]string, payload []byte) tea.Cmd {
+	return func() tea.Msg {
+		resp, _, err := client.Jobs().Dispatch(jobID, meta, payload, &api.WriteOptions{Namespace: jobNamespace})
+		if err != nil {
+			return JobDispatchFailedMsg{Err: err}
+		}
+		return JobDispatchedMsg{DispatchedJobID: resp.DispatchedJobID, EvalID: resp.EvalID}
+	}
+}
+
+func joinOrNone(s []string) string {
+	if len(s) == 0 {
+		return "none"
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,34 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"strings"
+	"time"
+)
+
+// ErrorRecord is an error that was toasted, kept so it can be read and copied in full after the toast is gone
+type ErrorRecord struct {
+	Time time.Time
+	Page Page
+	Err  string
+}
+
+// ListErrors shows the recorded errors newest first. Each row's key is its full error, to copy.
+func ListErrors(records []ErrorRecord) tea.Cmd {
+	return func() tea.Msg {
+		if len(records) == 0 {
+			return PageLoadedMsg{Page: ErrorsPage, TableHeader: []string{"No errors"}, AllPageRows: []page.Row{}}
+		}
+		var rows []page.Row
+		for i := len(records) - 1; i >= 0; i-- {
+			r := records[i]
+			// errors can span lines, e.g. from the Nomad API, but each row is a single line
+			row := fmt.Sprintf("%s  %-12s %s", formatter.FormatTime(r.Time), r.Page.String(), strings.Join(strings.Fields(r.Err), " "))
+			rows = append(rows, page.Row{Key: r.Err, Row: row})
+		}
+		return PageLoadedMsg{Page: ErrorsPage, TableHeader: []string{}, AllPageRows: rows}
+	}
+}
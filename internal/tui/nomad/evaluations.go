@@ -0,0 +1,164 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"time"
+)
+
+// EvaluationPolledMsg has the evaluation, or Err if it couldn't be fetched, in which case polling continues
+type EvaluationPolledMsg struct {
+	EvalID string
+	Eval   *api.Evaluation
+	Err    error
+}
+
+type PollEvaluationMsg struct {
+	ID     int
+	EvalID string
+}
+
+func LoadEvaluationPage(evalID string) tea.Cmd {
+	return func() tea.Msg {
+		// rows are appended as the evaluation is polled
+		return PageLoadedMsg{
+			Page:        EvaluationPage,
+			TableHeader: []string{},
+			AllPageRows: []page.Row{{Row: fmt.Sprintf("Following evaluation %s", evalID)}},
+		}
+	}
+}
+
+// FetchJobEvaluations lists the job's most recent evaluations, newest first, keyed by evaluation ID
+func FetchJobEvaluations(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		evals, _, err := client.Jobs().Evaluations(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		sort.Slice(evals, func(x, y int) bool {
+			return evals[x].CreateTime > evals[y].CreateTime
+		})
+		if len(evals) > constants.RecentEvaluationsCount {
+			evals = evals[:constants.RecentEvaluationsCount]
+		}
+
+		var evalRows [][]string
+		for _, eval := range evals {
+			evalRows = append(evalRows, []string{
+				formatter.ShortAllocID(eval.ID),
+				eval.Status,
+				eval.TriggeredBy,
+				formatter.FormatTimeNs(eval.CreateTime),
+				eval.StatusDescription,
+			})
+		}
+		columns := []string{"Eval ID", "Status", "Triggered By", "Created", "Description"}
+		table := formatter.GetRenderedTableAsString(columns, evalRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: evals[idx].ID, Row: row, Fields: map[string]string{
+				"id":           evals[idx].ID,
+				"status":       evals[idx].Status,
+				"triggered_by": evals[idx].TriggeredBy,
+			}})
+		}
+
+		return PageLoadedMsg{Page: JobEvaluationsPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func PollEvaluation(client api.Client, evalID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		eval, _, err := client.Evaluations().Info(evalID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return EvaluationPolledMsg{EvalID: evalID, Err: err}
+		}
+		return EvaluationPolledMsg{EvalID: evalID, Eval: eval}
+	}
+}
+
+func PollEvaluationWithDelay(id int, evalID string) tea.Cmd {
+	return tea.Tick(constants.EvaluationPollInterval, func(t time.Time) tea.Msg { return PollEvaluationMsg{ID: id, EvalID: evalID} })
+}
+
+// EvaluationDone is true once the evaluation reaches a terminal status
+func EvaluationDone(eval *api.Evaluation) bool {
+	switch eval.Status {
+	case "complete", "failed", "canceled":
+		return true
+	}
+	return false
+}
+
+// EvaluationSucceeded is true if the evaluation completed and placed everything
+func EvaluationSucceeded(eval *api.Evaluation) bool {
+	return eval.Status == "complete" && len(eval.FailedTGAllocs) == 0 && eval.BlockedEval == ""
+}
+
+func EvaluationStatusRow(eval *api.Evaluation) page.Row {
+	row := fmt.Sprintf("%s  %s  %s", formatter.FormatTime(time.Now()), formatter.ShortAllocID(eval.ID), eval.Status)
+	if eval.StatusDescription != "" {
+		row += fmt.Sprintf(" (%s)", eval.StatusDescription)
+	}
+	return page.Row{Row: row}
+}
+
+func EvaluationPollErrorRow(evalID string, err error) page.Row {
+	return page.Row{Row: fmt.Sprintf("%s  %s  failed to fetch, retrying: %v", formatter.FormatTime(time.Now()), formatter.ShortAllocID(evalID), err)}
+}
+
+// NextEvaluationInChain is the evaluation that continues a terminal one's work, if any: the blocked evaluation waiting
+// for resources to place what it couldn't, otherwise the next evaluation it scheduled
+func NextEvaluationInChain(eval *api.Evaluation) string {
+	if eval.BlockedEval != "" {
+		return eval.BlockedEval
+	}
+	return eval.NextEval
+}
+
+// EvaluationResultRows describes the blocked eval chain and any placement failures of a terminal evaluation
+func EvaluationResultRows(eval *api.Evaluation) []page.Row {
+	var rows []page.Row
+	if eval.NextEval != "" {
+		rows = append(rows, page.Row{Row: fmt.Sprintf("Next evaluation: %s", eval.NextEval)})
+	}
+	if eval.BlockedEval != "" {
+		rows = append(rows, page.Row{Row: fmt.Sprintf("Blocked evaluation created, waiting for resources: %s", eval.BlockedEval)})
+	}
+
+	var taskGroups []string
+	for tg := range eval.FailedTGAllocs {
+		taskGroups = append(taskGroups, tg)
+	}
+	sort.Strings(taskGroups)
+	for _, tg := range taskGroups {
+		metric := eval.FailedTGAllocs[tg]
+		rows = append(rows, page.Row{Row: fmt.Sprintf(
+			"Task group %s failed to place: %d nodes evaluated, %d filtered, %d exhausted",
+			tg, metric.NodesEvaluated, metric.NodesFiltered, metric.NodesExhausted,
+		)})
+		for _, reasons := range []map[string]int{metric.ConstraintFiltered, metric.ClassFiltered, metric.DimensionExhausted, metric.ClassExhausted} {
+			for _, reason := range sortedKeys(reasons) {
+				rows = append(rows, page.Row{Row: fmt.Sprintf("  %s: %d", reason, reasons[reason])})
+			}
+		}
+	}
+	return rows
+}
+
+func sortedKeys(m map[string]int) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -19,6 +19,12 @@ type EventsStreamMsg struct {
 	Topics        Topics
 }
 
+// EventsStreamErrMsg is sent when the events stream fails, e.g. as its token was rotated
+type EventsStreamErrMsg struct {
+	Err    error
+	Topics Topics
+}
+
 func FetchEventsStream(client api.Client, topics Topics, namespace string, page Page) tea.Cmd {
 	return func() tea.Msg {
 		eventsChan, err := client.EventStream().Stream(context.Background(), topics, 0, &api.QueryOptions{Namespace: namespace})
@@ -32,12 +38,15 @@ func FetchEventsStream(client api.Client, topics Topics, namespace string, page
 func ReadEventsStreamNextMessage(c EventsStream, code *gojq.Code) tea.Cmd {
 	return func() tea.Msg {
 		line := <-c.Chan
+		if line != nil && line.Err != nil {
+			return EventsStreamErrMsg{Err: line.Err, Topics: c.Topics}
+		}
 		lineBytes, err := json.Marshal(line)
 		if err != nil {
 			return message.ErrMsg{Err: err}
 		}
 		trimmed := strings.TrimSpace(string(lineBytes))
-		jq, err := runJQQueryOnEvent(trimmed, code)
+		jq, err := RunJQQueryOnEvent(trimmed, code)
 		if err != nil {
 			return message.ErrMsg{Err: err}
 		}
@@ -45,6 +54,36 @@ func ReadEventsStreamNextMessage(c EventsStream, code *gojq.Code) tea.Cmd {
 	}
 }
 
+// EventsAsJQLines runs the jq query on the events, returning each result as a line of JSON
+func EventsAsJQLines(events *api.Events, code *gojq.Code) ([]string, error) {
+	eventsBytes, err := json.Marshal(events)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	if err = json.Unmarshal(eventsBytes, &result); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	iter := code.Run(result)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, string(j))
+	}
+	return lines, nil
+}
+
 func formatEventTopics(topics Topics) string {
 	t := ""
 	for k, v := range topics {
@@ -77,7 +116,8 @@ func TopicsForAlloc(topics Topics, allocID string) Topics {
 	return t
 }
 
-func runJQQueryOnEvent(event string, code *gojq.Code) (string, error) {
+// RunJQQueryOnEvent returns the first result of the jq query on the event JSON, or the event if there are no results
+func RunJQQueryOnEvent(event string, code *gojq.Code) (string, error) {
 	result := make(map[string]interface{})
 	err := json.Unmarshal([]byte(event), &result)
 	if err != nil {
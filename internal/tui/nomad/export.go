@@ -0,0 +1,92 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/fileio"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+const redacted = "<redacted>"
+
+type ExportFormat string
+
+const (
+	ExportJSON ExportFormat = "json"
+	ExportYAML ExportFormat = "yaml"
+)
+
+type AllocationsExportedMsg struct {
+	Path  string
+	Count int
+}
+
+// ExportAllocations saves the full allocation objects for a job to a file in the current directory, named by job
+// and time
+func ExportAllocations(client api.Client, jobID, jobNamespace string, format ExportFormat) tea.Cmd {
+	return func() tea.Msg {
+		stubs, _, err := client.Jobs().Allocations(jobID, true, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+
+		var allocs []*api.Allocation
+		for _, stub := range stubs {
+			alloc, _, err := client.Allocations().Info(stub.ID, &api.QueryOptions{Namespace: jobNamespace})
+			if err != nil {
+				return message.ToastMsg{Err: err}
+			}
+			redactAllocationSecrets(alloc)
+			allocs = append(allocs, alloc)
+		}
+
+		content, err := marshalExport(allocs, format)
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+
+		fileName := fmt.Sprintf("%s_allocations_%s.%s", jobID, time.Now().Format("20060102T150405"), format)
+		path, err := fileio.SaveToFile(fileName, content)
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return AllocationsExportedMsg{Path: path, Count: len(allocs)}
+	}
+}
+
+// redactAllocationSecrets removes tokens the job was submitted with from the allocation's copy of the job
+func redactAllocationSecrets(alloc *api.Allocation) {
+	if alloc.Job == nil {
+		return
+	}
+	for _, token := range []*string{alloc.Job.VaultToken, alloc.Job.ConsulToken} {
+		if token != nil && *token != "" {
+			*token = redacted
+		}
+	}
+}
+
+func marshalExport(v interface{}, format ExportFormat) (string, error) {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if format == ExportJSON {
+		return string(jsonBytes), nil
+	}
+
+	// round trip through json so yaml keys match the Nomad API field names
+	var generic interface{}
+	if err = json.Unmarshal(jsonBytes, &generic); err != nil {
+		return "", err
+	}
+	yamlBytes, err := yaml.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+	return string(yamlBytes), nil
+}
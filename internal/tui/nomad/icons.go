@@ -0,0 +1,32 @@
+package nomad
+
+// IconSet is the glyphs shown next to job and task statuses, as not every terminal has a nerd font
+type IconSet string
+
+const (
+	NoIcons       IconSet = "none"
+	ASCIIIcons    IconSet = "ascii"
+	NerdFontIcons IconSet = "nerdfont"
+)
+
+var statusIcons = map[IconSet]map[string]string{
+	ASCIIIcons: {
+		"running": "+",
+		"pending": "~",
+		"dead":    "x",
+	},
+	// nf-fa-check, nf-fa-clock_o and nf-fa-times
+	NerdFontIcons: {
+		"running": "\uf00c",
+		"pending": "\uf017",
+		"dead":    "\uf00d",
+	},
+}
+
+// withStatusIcon prefixes status with its icon in icons, if it has one. Filters match on the status without its icon.
+func withStatusIcon(status string, icons IconSet) string {
+	if icon, ok := statusIcons[icons][status]; ok {
+		return icon + " " + status
+	}
+	return status
+}
@@ -0,0 +1,152 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const defaultEditor = "vi"
+
+type JobSpecReadyForEditMsg struct {
+	Path string
+}
+
+type JobSpecEditedMsg struct {
+	Path string
+	Err  error
+}
+
+type JobPlannedMsg struct {
+	Job            *api.Job
+	JobModifyIndex uint64
+	Summary        string
+}
+
+type JobRegisteredMsg struct {
+	JobID, EvalID string
+}
+
+// PrepareJobSpecForEdit writes the current job spec as indented JSON to a temporary file
+func PrepareJobSpecForEdit(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+
+		jobBytes, err := json.MarshalIndent(job, "", "  ")
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+
+		// child job IDs, e.g. of periodic and dispatched jobs, include a "/", which can't be in the pattern
+		f, err := os.CreateTemp("", fmt.Sprintf("wander-%s-*.json", strings.NewReplacer("/", "-", string(os.PathSeparator), "-").Replace(jobID)))
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		defer f.Close()
+
+		if _, err = f.Write(jobBytes); err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return JobSpecReadyForEditMsg{Path: f.Name()}
+	}
+}
+
+// EditJobSpec opens the file at path in $EDITOR, suspending the program until the editor exits
+func EditJobSpec(path string) tea.Cmd {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{defaultEditor}
+	}
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return JobSpecEditedMsg{Path: path, Err: err}
+	})
+}
+
+// PlanEditedJobSpec parses the edited job spec and plans it, removing the temporary file
+func PlanEditedJobSpec(client api.Client, path, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		defer os.Remove(path)
+
+		jobBytes, err := os.ReadFile(path)
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+
+		var job api.Job
+		if err = json.Unmarshal(jobBytes, &job); err != nil {
+			return message.ToastMsg{Err: fmt.Errorf("invalid job spec: %w", err)}
+		}
+		if job.ID == nil {
+			return message.ToastMsg{Err: fmt.Errorf("invalid job spec: missing ID")}
+		}
+
+		plan, _, err := client.Jobs().Plan(&job, true, &api.WriteOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: fmt.Errorf("plan failed: %w", err)}
+		}
+		if plan.Diff != nil && plan.Diff.Type == "None" {
+			return message.ToastMsg{Message: "No changes to job spec"}
+		}
+
+		return JobPlannedMsg{Job: &job, JobModifyIndex: plan.JobModifyIndex, Summary: formatPlanSummary(plan)}
+	}
+}
+
+// RegisterJob submits the job, failing if it was modified since it was planned
+func RegisterJob(client api.Client, job *api.Job, jobNamespace string, jobModifyIndex uint64) tea.Cmd {
+	return func() tea.Msg {
+		opts := &api.RegisterOptions{EnforceIndex: true, ModifyIndex: jobModifyIndex}
+		resp, _, err := client.Jobs().RegisterOpts(job, opts, &api.WriteOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ToastMsg{Err: err}
+		}
+		return JobRegisteredMsg{JobID: *job.ID, EvalID: resp.EvalID}
+	}
+}
+
+func formatPlanSummary(plan *api.JobPlanResponse) string {
+	if plan.Annotations == nil {
+		return ""
+	}
+
+	var groupNames []string
+	for name := range plan.Annotations.DesiredTGUpdates {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	var groups []string
+	for _, name := range groupNames {
+		u := plan.Annotations.DesiredTGUpdates[name]
+		var changes []string
+		for _, c := range []struct {
+			desc  string
+			count uint64
+		}{
+			{"create", u.Place},
+			{"destroy", u.Stop},
+			{"migrate", u.Migrate},
+			{"in-place update", u.InPlaceUpdate},
+			{"create/destroy update", u.DestructiveUpdate},
+			{"canary", u.Canary},
+		} {
+			if c.count > 0 {
+				changes = append(changes, fmt.Sprintf("%d %s", c.count, c.desc))
+			}
+		}
+		if len(changes) > 0 {
+			groups = append(groups, fmt.Sprintf("%s: %s", name, strings.Join(changes, ", ")))
+		}
+	}
+	return strings.Join(groups, "; ")
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"errors"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/nomad/api"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
@@ -12,16 +13,58 @@ import (
 	"strings"
 )
 
-func FetchJobs(client api.Client) tea.Cmd {
+// JobTypes are the job types the jobs page can be narrowed to, in the order they're cycled through. "" is all types.
+var JobTypes = []string{"", "service", "batch", "system", "sysbatch"}
+
+// NextJobType is the job type after jobType in JobTypes, wrapping around to all types
+func NextJobType(jobType string) string {
+	for i, t := range JobTypes {
+		if t == jobType {
+			return JobTypes[(i+1)%len(JobTypes)]
+		}
+	}
+	return ""
+}
+
+// FetchJobs fetches the jobs list. The list doesn't include job meta, so if withMeta, each job is also fetched to allow
+// filtering by meta, which is slower. Jobs can always be filtered by datacenter, but only show a column for it if
+// showDatacenters. Dead jobs, i.e. stopped or complete, are left out if hideDead, as are jobs not of jobType if set.
+// If perPage is set, only the first pages of perPage jobs are fetched, or all of them page by page if pages is 0.
+func FetchJobs(client api.Client, withMeta, showDatacenters, hideDead bool, jobType string, perPage, pages int, icons IconSet, fetchID int) tea.Cmd {
 	return func() tea.Msg {
-		jobResults, _, err := client.Jobs().List(nil)
-		if err != nil {
-			if strings.Contains(err.Error(), "UUID must be 36 characters") {
-				return message.ErrMsg{Err: errors.New("token must be 36 characters")}
-			} else if strings.Contains(err.Error(), "ACL token not found") {
-				return message.ErrMsg{Err: errors.New("token not authorized to list jobs")}
+		var jobResults []*api.JobListStub
+		var nextToken string
+		var fetched int
+		for pages == 0 || fetched < pages {
+			results, meta, err := client.Jobs().List(&api.QueryOptions{PerPage: int32(perPage), NextToken: nextToken})
+			if err != nil {
+				if strings.Contains(err.Error(), "UUID must be 36 characters") {
+					return message.ErrMsg{Err: errors.New("token must be 36 characters")}
+				} else if strings.Contains(err.Error(), "ACL token not found") {
+					return message.ErrMsg{Err: errors.New("token not authorized to list jobs")}
+				}
+				return message.ErrMsg{Err: err}
+			}
+			jobResults = append(jobResults, results...)
+			nextToken = meta.NextToken
+			fetched++
+			if perPage == 0 || nextToken == "" {
+				break
+			}
+		}
+
+		if hideDead || jobType != "" {
+			var shown []*api.JobListStub
+			for _, j := range jobResults {
+				if hideDead && j.Status == "dead" {
+					continue
+				}
+				if jobType != "" && j.Type != jobType {
+					continue
+				}
+				shown = append(shown, j)
 			}
-			return message.ErrMsg{Err: err}
+			jobResults = shown
 		}
 
 		sort.Slice(jobResults, func(x, y int) bool {
@@ -33,12 +76,38 @@ func FetchJobs(client api.Client) tea.Cmd {
 			return jobResults[x].Name < jobResults[y].Name
 		})
 
-		tableHeader, allPageData := jobResponsesAsTable(jobResults)
-		return PageLoadedMsg{Page: JobsPage, TableHeader: tableHeader, AllPageRows: allPageData}
+		metaByJob := make(map[string]map[string]string)
+		if withMeta {
+			for _, j := range jobResults {
+				job, _, err := client.Jobs().Info(j.ID, &api.QueryOptions{Namespace: j.Namespace})
+				if err != nil {
+					return message.ErrMsg{Err: err}
+				}
+				metaByJob[toJobsKey(j)] = job.Meta
+			}
+		}
+
+		tableHeader, allPageData := jobResponsesAsTable(jobResults, metaByJob, showDatacenters, icons)
+		return PageLoadedMsg{Page: JobsPage, TableHeader: tableHeader, AllPageRows: allPageData, MoreAvailable: nextToken != "", FetchID: fetchID}
+	}
+}
+
+// JobsFilterPrefix notes the job type the jobs page is narrowed to and whether dead jobs are hidden, if either
+func JobsFilterPrefix(hideDead bool, jobType string) string {
+	var notes []string
+	if jobType != "" {
+		notes = append(notes, jobType+" only")
+	}
+	if hideDead {
+		notes = append(notes, "dead hidden")
 	}
+	if len(notes) == 0 {
+		return "Jobs"
+	}
+	return fmt.Sprintf("Jobs (%s)", strings.Join(notes, ", "))
 }
 
-func jobResponsesAsTable(jobResponse []*api.JobListStub) ([]string, []page.Row) {
+func jobResponsesAsTable(jobResponse []*api.JobListStub, metaByJob map[string]map[string]string, showDatacenters bool, icons IconSet) ([]string, []page.Row) {
 	var jobResponseRows [][]string
 	var keys []string
 	for _, row := range jobResponse {
@@ -53,32 +122,66 @@ func jobResponsesAsTable(jobResponse []*api.JobListStub) ([]string, []page.Row)
 		}
 		count := strconv.Itoa(num) + "/" + strconv.Itoa(denom)
 
-		jobResponseRows = append(jobResponseRows, []string{
-			row.ID,
-			row.Type,
-			row.Namespace,
+		jobResponseRow := []string{row.ID, row.Type, row.Namespace}
+		if showDatacenters {
+			jobResponseRow = append(jobResponseRow, strings.Join(row.Datacenters, ","))
+		}
+		jobResponseRow = append(jobResponseRow,
 			strconv.Itoa(row.Priority),
-			row.Status,
+			withStatusIcon(row.Status, icons),
 			count,
 			formatter.FormatTimeNs(row.SubmitTime),
 			uptime,
-		})
+		)
+		jobResponseRows = append(jobResponseRows, jobResponseRow)
 		keys = append(keys, toJobsKey(row))
 	}
 
-	columns := []string{"ID", "Type", "Namespace", "Priority", "Status", "Count", "Submitted", "Since Submit"}
+	columns := []string{"ID", "Type", "Namespace"}
+	if showDatacenters {
+		columns = append(columns, "Datacenters")
+	}
+	columns = append(columns, "Priority", "Status", "Count", "Submitted", "Since Submit")
 	table := formatter.GetRenderedTableAsString(columns, jobResponseRows)
 
 	var rows []page.Row
 	for idx, row := range table.ContentRows {
-		rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		rows = append(rows, page.Row{Key: keys[idx], Row: row, Fields: jobFilterFields(jobResponse[idx], metaByJob[keys[idx]])})
 	}
 
 	return table.HeaderRows, rows
 }
 
+func jobFilterFields(job *api.JobListStub, meta map[string]string) map[string]string {
+	fields := map[string]string{
+		"id":        job.ID,
+		"name":      job.Name,
+		"type":      job.Type,
+		"namespace": job.Namespace,
+		"priority":  strconv.Itoa(job.Priority),
+		"status":    job.Status,
+		"submitted": formatter.FormatTimeNs(job.SubmitTime),
+		// jobs can have several datacenters, so filter with datacenters~dc1
+		"datacenters": strings.Join(job.Datacenters, ","),
+	}
+	for k, v := range meta {
+		fields[page.MetaFieldPrefix+k] = v
+	}
+	return fields
+}
+
+// FilterUsesMeta is true if the filter matches on job meta, requiring it to be fetched
+func FilterUsesMeta(filter string) bool {
+	for _, term := range strings.Fields(filter) {
+		if strings.HasPrefix(strings.ToLower(term), page.MetaFieldPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func toJobsKey(jobResponseEntry *api.JobListStub) string {
-	return jobResponseEntry.ID + " " + jobResponseEntry.Namespace
+	return JobKey(jobResponseEntry.ID, jobResponseEntry.Namespace)
 }
 
 func JobIDAndNamespaceFromKey(key string) (string, string) {
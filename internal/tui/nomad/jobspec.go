@@ -2,14 +2,28 @@ package nomad
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/nomad/api"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
 	"github.com/robinovitch61/wander/internal/tui/formatter"
 	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/style"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 )
 
-func FetchJobSpec(client api.Client, jobID, jobNamespace string) tea.Cmd {
+// jobSubmission is the source a job version was submitted with, kept by Nomad 1.6 and later. Nomad doesn't record who
+// submitted a job, so only the submit time and source are shown.
+type jobSubmission struct {
+	Source string
+	Format string
+}
+
+func FetchJobSpec(client api.Client, jobID, jobNamespace string, showSource bool) tea.Cmd {
 	return func() tea.Msg {
 		jobSpec, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
 		if err != nil {
@@ -20,17 +34,169 @@ func FetchJobSpec(client api.Client, jobID, jobNamespace string) tea.Cmd {
 		if err != nil {
 			return message.ErrMsg{Err: err}
 		}
-		pretty := formatter.PrettyJsonStringAsLines(string(jobBytes))
+		lines := formatter.PrettyJsonStringAsLines(string(jobBytes))
+
+		submission, submissionErr := fetchJobSubmission(client, jobID, jobNamespace, jobSpec.Version)
+		sourceInfo := "source not available"
+		if submissionErr == nil {
+			sourceInfo = fmt.Sprintf("%s source available", submission.Format)
+		}
+		if showSource {
+			if submissionErr == nil {
+				lines = strings.Split(strings.TrimRight(submission.Source, "\n"), "\n")
+			} else {
+				lines = []string{"The submitted source is not available. Nomad keeps it from version 1.6, for jobs submitted with it."}
+			}
+		}
 
 		var jobSpecPageData []page.Row
-		for _, row := range pretty {
+		for _, row := range lines {
 			jobSpecPageData = append(jobSpecPageData, page.Row{Key: "", Row: row})
 		}
 
+		tableHeader := []string{fmt.Sprintf(
+			"Version %d submitted %s, %s", derefUint64(jobSpec.Version), formatter.FormatTimeNs(derefInt64(jobSpec.SubmitTime)), sourceInfo,
+		)}
+
+		if jobSpec.Periodic != nil {
+			tableHeader = append(tableHeader, periodicSummary(client, jobSpec))
+		}
+
+		tableHeader = append(tableHeader, strategySummary(jobSpec)...)
+
+		// meta is often used for ownership and environment, so show it above the spec
+		if len(jobSpec.Meta) > 0 {
+			var keys []string
+			for k := range jobSpec.Meta {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var meta []string
+			for _, k := range keys {
+				meta = append(meta, fmt.Sprintf("%s=%s", k, jobSpec.Meta[k]))
+			}
+			tableHeader = append(tableHeader, "Meta: "+strings.Join(meta, "  "))
+		}
+
 		return PageLoadedMsg{
 			Page:        JobSpecPage,
-			TableHeader: []string{},
+			TableHeader: tableHeader,
 			AllPageRows: jobSpecPageData,
 		}
 	}
 }
+
+// periodicSummary describes a periodic job's schedule, when it next runs, and the child job it last launched
+func periodicSummary(client api.Client, job *api.Job) string {
+	p := job.Periodic
+	timeZone := derefString(p.TimeZone)
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	summary := fmt.Sprintf("Periodic: %s %q in %s", derefString(p.SpecType), derefString(p.Spec), timeZone)
+
+	switch location, err := p.GetLocation(); {
+	case p.Enabled != nil && !*p.Enabled:
+		summary += ", disabled"
+	case job.Stop != nil && *job.Stop:
+		summary += ", stopped"
+	case err != nil:
+		summary += fmt.Sprintf(", next run unknown: %v", err)
+	default:
+		// Nomad evaluates the spec in the job's time zone
+		next, err := p.Next(time.Now().In(location))
+		if err != nil {
+			summary += fmt.Sprintf(", next run unknown: %v", err)
+		} else if !next.IsZero() {
+			summary += fmt.Sprintf(", next run %s", formatter.FormatTime(next))
+		}
+	}
+
+	// Nomad names the jobs it launches <parent>/periodic-<launch time>
+	children, _, err := client.Jobs().List(&api.QueryOptions{Prefix: derefString(job.ID) + "/periodic-", Namespace: derefString(job.Namespace)})
+	if err != nil {
+		return summary
+	}
+	var last *api.JobListStub
+	for _, c := range children {
+		if c.ParentID == derefString(job.ID) && (last == nil || c.SubmitTime > last.SubmitTime) {
+			last = c
+		}
+	}
+	if last == nil {
+		return summary + ", not launched yet"
+	}
+	return summary + fmt.Sprintf(", last launched %s at %s (%s)", last.ID, formatter.FormatTimeNs(last.SubmitTime), last.Status)
+}
+
+func JobSourceFilterPrefix(jobID string) string {
+	return fmt.Sprintf("Submitted Source for %s", style.Bold.Render(jobID))
+}
+
+func fetchJobSubmission(client api.Client, jobID, jobNamespace string, version *uint64) (jobSubmission, error) {
+	var submission jobSubmission
+	endpoint := fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), derefUint64(version))
+	_, err := client.Raw().Query(endpoint, &submission, &api.QueryOptions{Namespace: jobNamespace})
+	if err == nil && submission.Source == "" {
+		err = errors.New("no source submitted")
+	}
+	return submission, err
+}
+
+// strategySummary describes how the job's groups are deployed and migrated off draining nodes, to predict disruption.
+// Nomad merges the job's update block into each group's, so the job's is only shown if no group has one.
+func strategySummary(job *api.Job) []string {
+	var summary []string
+	groupsHaveUpdate := false
+	for _, tg := range job.TaskGroups {
+		var parts []string
+		if tg.Update != nil {
+			groupsHaveUpdate = true
+			parts = append(parts, "update "+updateSummary(tg.Update))
+		}
+		if m := tg.Migrate; m != nil {
+			parts = append(parts, fmt.Sprintf(
+				"migrate max_parallel %d, health_check %s, min_healthy_time %s, healthy_deadline %s",
+				derefInt(m.MaxParallel), derefString(m.HealthCheck), derefDuration(m.MinHealthyTime), derefDuration(m.HealthyDeadline),
+			))
+		}
+		if len(parts) > 0 {
+			summary = append(summary, fmt.Sprintf("Group %s: %s", derefString(tg.Name), strings.Join(parts, "; ")))
+		}
+	}
+	if !groupsHaveUpdate && job.Update != nil && derefInt(job.Update.MaxParallel) > 0 {
+		summary = append([]string{"Update: " + updateSummary(job.Update)}, summary...)
+	}
+	return summary
+}
+
+func updateSummary(u *api.UpdateStrategy) string {
+	summary := fmt.Sprintf(
+		"max_parallel %d, stagger %s, health_check %s, min_healthy_time %s, healthy_deadline %s, progress_deadline %s",
+		derefInt(u.MaxParallel), derefDuration(u.Stagger), derefString(u.HealthCheck), derefDuration(u.MinHealthyTime), derefDuration(u.HealthyDeadline), derefDuration(u.ProgressDeadline),
+	)
+	if canary := derefInt(u.Canary); canary > 0 {
+		summary += fmt.Sprintf(", canary %d", canary)
+		if derefBool(u.AutoPromote) {
+			summary += ", auto_promote"
+		}
+	}
+	if derefBool(u.AutoRevert) {
+		summary += ", auto_revert"
+	}
+	return summary
+}
+
+func derefUint64(i *uint64) uint64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func derefInt64(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -0,0 +1,123 @@
+package nomad
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const linkScheme = "wander"
+
+// linkPages are the pages a link can open, by their name in the link
+var linkPages = map[string]Page{
+	"jobs":            JobsPage,
+	"job-spec":        JobSpecPage,
+	"allocations":     AllocationsPage,
+	"allocation-spec": AllocSpecPage,
+	"logs":            LogsPage,
+	"task-events":     TaskEventsPage,
+	"node-spec":       NodeSpecPage,
+}
+
+// Link identifies a view to share it, e.g. wander://logs?alloc=<id>&task=web, opened on startup with --open
+type Link struct {
+	Page              Page
+	Namespace, Region string
+	JobID             string
+	AllocID, TaskName string
+	LogType           LogType
+	NodeID            string
+	Filter            string
+}
+
+func IsLinkable(p Page) bool {
+	for _, linkPage := range linkPages {
+		if linkPage == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (l Link) String() string {
+	var name string
+	for n, p := range linkPages {
+		if p == l.Page {
+			name = n
+		}
+	}
+	values := url.Values{}
+	set := func(k, v string) {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	set("namespace", l.Namespace)
+	set("region", l.Region)
+	set("job", l.JobID)
+	set("alloc", l.AllocID)
+	set("task", l.TaskName)
+	if l.Page == LogsPage && l.LogType == StdErr {
+		set("log", "stderr")
+	}
+	set("node", l.NodeID)
+	set("filter", l.Filter)
+	return (&url.URL{Scheme: linkScheme, Host: name, RawQuery: values.Encode()}).String()
+}
+
+// ParseLink parses a link copied from wander, checking it has what its page needs to load
+func ParseLink(s string) (Link, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Link{}, err
+	}
+	if u.Scheme != linkScheme {
+		return Link{}, fmt.Errorf("link %s must start with %s://", s, linkScheme)
+	}
+	page, ok := linkPages[u.Host]
+	if !ok {
+		var names []string
+		for n := range linkPages {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Link{}, fmt.Errorf("link page %s must be one of %s", u.Host, strings.Join(names, ", "))
+	}
+
+	q := u.Query()
+	l := Link{
+		Page:      page,
+		Namespace: q.Get("namespace"),
+		Region:    q.Get("region"),
+		JobID:     q.Get("job"),
+		AllocID:   q.Get("alloc"),
+		TaskName:  q.Get("task"),
+		LogType:   StdOut,
+		NodeID:    q.Get("node"),
+		Filter:    q.Get("filter"),
+	}
+	if q.Get("log") == "stderr" {
+		l.LogType = StdErr
+	}
+
+	var missing string
+	switch page {
+	case JobSpecPage, AllocationsPage:
+		if l.JobID == "" {
+			missing = "job"
+		}
+	case AllocSpecPage, LogsPage, TaskEventsPage:
+		if l.AllocID == "" {
+			missing = "alloc"
+		}
+	case NodeSpecPage:
+		if l.NodeID == "" {
+			missing = "node"
+		}
+	}
+	if missing != "" {
+		return Link{}, fmt.Errorf("link to %s requires %s", u.Host, missing)
+	}
+	return l, nil
+}
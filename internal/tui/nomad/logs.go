@@ -1,12 +1,23 @@
 package nomad
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/nomad/api"
 	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/constants"
 	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/style"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 type LogType int8
@@ -36,56 +47,458 @@ func (p LogType) ShortString() string {
 	return "unknown"
 }
 
-func FetchLogs(client api.Client, alloc api.Allocation, taskName string, logType LogType, logOffset int) tea.Cmd {
+func FetchLogs(client api.Client, alloc api.Allocation, taskName string, logType LogType, logOffset int, fromStart bool, redactions []*regexp.Regexp, prettyJSON bool, maxLineLength int) tea.Cmd {
 	return func() tea.Msg {
-		// This is currently very important and strange. The logs api attempts to go through the node directly
-		// by default. The default timeout for this is 1 second. If it fails, it falls silently to going through
-		// the server. Since it always fails, at least in my Nomad setup, make it timeout immediately by setting
-		// the timeout to something tiny.
-		api.ClientConnTimeout = 1 * time.Microsecond
+		logRows, fellBack := fetchLogRows(client, alloc, taskName, logType, logOffset, fromStart, redactions)
+		tableHeader, allPageData := logsAsTable(toLogLines(logRows, prettyJSON, ""), logsColumn(logType, logOffset, fromStart, fellBack), maxLineLength)
+		return PageLoadedMsg{Page: LogsPage, TableHeader: tableHeader, AllPageRows: allPageData}
+	}
+}
 
-		closeLogConn := make(chan struct{})   // never closed for now
-		logsChan, _ := client.AllocFS().Logs( // TODO LEO: deal with error channel
-			&alloc,
-			false,
-			taskName,
-			logType.ShortString(),
-			"end",
-			int64(logOffset),
-			closeLogConn,
-			nil,
-		)
+// FetchMergedLogs fetches the logs of the task in all running allocations of the task group, interleaving lines by
+// timestamp and prefixing each with its short allocation ID. Ordering is best-effort, as a line without a leading
+// timestamp is ordered as if logged at the same time as the previous line from its allocation.
+func FetchMergedLogs(client api.Client, alloc api.Allocation, taskName string, logType LogType, logOffset int, fromStart bool, redactions []*regexp.Regexp, prettyJSON bool, maxLineLength int) tea.Cmd {
+	return func() tea.Msg {
+		stubs, _, err := client.Jobs().Allocations(alloc.JobID, false, &api.QueryOptions{Namespace: alloc.Namespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
 
-		allLogs := ""
-		for l := range logsChan {
-			allLogs += string(l.Data)
+		var allocIDs []string
+		for _, stub := range stubs {
+			if stub.TaskGroup == alloc.TaskGroup && stub.ClientStatus == "running" {
+				allocIDs = append(allocIDs, stub.ID)
+			}
+		}
+		if len(allocIDs) == 0 {
+			// e.g. the selected allocation is no longer running, so show what it has
+			allocIDs = []string{alloc.ID}
 		}
 
-		trimmedBody := strings.ReplaceAll(allLogs, "\t", "    ")
-		logRows := strings.Split(formatter.StripANSI(trimmedBody), "\n")
+		// fan out as fetching logs from many allocations one at a time is slow
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var lines []mergedLogLine
+		var fetchErr error
+		var anyFellBack bool
+		for _, allocID := range allocIDs {
+			wg.Add(1)
+			go func(allocID string) {
+				defer wg.Done()
+				fullAlloc, _, err := client.Allocations().Info(allocID, nil)
+				if err != nil {
+					mu.Lock()
+					fetchErr = err
+					mu.Unlock()
+					return
+				}
+				logRows, fellBack := fetchLogRows(client, *fullAlloc, taskName, logType, logOffset, fromStart, redactions)
+				allocLines := toMergedLogLines(allocID, logRows)
+				mu.Lock()
+				lines = append(lines, allocLines...)
+				anyFellBack = anyFellBack || fellBack
+				mu.Unlock()
+			}(allocID)
+		}
+		wg.Wait()
+		if fetchErr != nil {
+			return message.ErrMsg{Err: fetchErr}
+		}
+
+		sortMergedLogLines(lines)
+
+		var logLines []logLine
+		for _, l := range lines {
+			logLines = append(logLines, toLogLines([]string{l.Line}, prettyJSON, formatter.ShortAllocID(l.Source)+" ")...)
+		}
 
-		tableHeader, allPageData := logsAsTable(logRows, logType)
+		tableHeader, allPageData := logsAsTable(logLines, logsColumn(logType, logOffset, fromStart, anyFellBack), maxLineLength)
 		return PageLoadedMsg{Page: LogsPage, TableHeader: tableHeader, AllPageRows: allPageData}
 	}
 }
 
-func logsAsTable(logs []string, logType LogType) ([]string, []page.Row) {
+// FetchAllTaskLogs fetches the logs of every task in the allocation, e.g. an app and its sidecars, interleaving lines by
+// timestamp and prefixing each with its task name. Ordering is best-effort, as in FetchMergedLogs.
+func FetchAllTaskLogs(client api.Client, alloc api.Allocation, logType LogType, logOffset int, fromStart bool, redactions []*regexp.Regexp, prettyJSON bool, maxLineLength int) tea.Cmd {
+	return func() tea.Msg {
+		var taskNames []string
+		nameWidth := 0
+		for taskName := range alloc.TaskStates {
+			taskNames = append(taskNames, taskName)
+			if len(taskName) > nameWidth {
+				nameWidth = len(taskName)
+			}
+		}
+		sort.Strings(taskNames)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var lines []mergedLogLine
+		var anyFellBack bool
+		for _, taskName := range taskNames {
+			wg.Add(1)
+			go func(taskName string) {
+				defer wg.Done()
+				logRows, fellBack := fetchLogRows(client, alloc, taskName, logType, logOffset, fromStart, redactions)
+				taskLines := toMergedLogLines(taskName, logRows)
+				mu.Lock()
+				lines = append(lines, taskLines...)
+				anyFellBack = anyFellBack || fellBack
+				mu.Unlock()
+			}(taskName)
+		}
+		wg.Wait()
+
+		sortMergedLogLines(lines)
+
+		var logLines []logLine
+		for _, l := range lines {
+			logLines = append(logLines, toLogLines([]string{l.Line}, prettyJSON, fmt.Sprintf("%-*s ", nameWidth, l.Source))...)
+		}
+
+		tableHeader, allPageData := logsAsTable(logLines, logsColumn(logType, logOffset, fromStart, anyFellBack), maxLineLength)
+		return PageLoadedMsg{Page: LogsPage, TableHeader: tableHeader, AllPageRows: allPageData}
+	}
+}
+
+// TaskLogPath is the path of the task's current log file on its client node, given the node's Nomad data_dir
+func TaskLogPath(dataDir, allocID, taskName string, logType LogType) string {
+	return path.Join(dataDir, "alloc", allocID, "alloc", "logs", fmt.Sprintf("%s.%s.0", taskName, logType.ShortString()))
+}
+
+func MergedLogsFilterPrefix(taskName, taskGroup string) string {
+	return fmt.Sprintf("Merged Logs for %s in %s", style.Bold.Render(taskName), taskGroup)
+}
+
+func AllTaskLogsFilterPrefix(allocID string) string {
+	return fmt.Sprintf("Logs for %s %s", style.Bold.Render("all tasks"), formatter.ShortAllocID(allocID))
+}
+
+// fetchLogRows reads logOffset bytes back from the end of the logs, or the logs from the start if fromStart. If the end
+// is empty, e.g. as the log file just rotated, it falls back to reading from the start, indicated by fellBack.
+func fetchLogRows(client api.Client, alloc api.Allocation, taskName string, logType LogType, logOffset int, fromStart bool, redactions []*regexp.Regexp) (logRows []string, fellBack bool) {
+	if fromStart {
+		return readLogRows(client, alloc, taskName, logType, "start", 0, redactions), false
+	}
+	logRows = readLogRows(client, alloc, taskName, logType, "end", int64(logOffset), redactions)
+	for _, row := range logRows {
+		if strings.TrimSpace(row) != "" {
+			return logRows, false
+		}
+	}
+	return readLogRows(client, alloc, taskName, logType, "start", 0, redactions), true
+}
+
+func readLogRows(client api.Client, alloc api.Allocation, taskName string, logType LogType, origin string, offset int64, redactions []*regexp.Regexp) []string {
+	// This is currently very important and strange. The logs api attempts to go through the node directly
+	// by default. The default timeout for this is 1 second. If it fails, it falls silently to going through
+	// the server. Since it always fails, at least in my Nomad setup, make it timeout immediately by setting
+	// the timeout to something tiny.
+	api.ClientConnTimeout = 1 * time.Microsecond
+
+	closeLogConn := make(chan struct{})   // never closed for now
+	logsChan, _ := client.AllocFS().Logs( // TODO LEO: deal with error channel
+		&alloc,
+		false,
+		taskName,
+		logType.ShortString(),
+		origin,
+		offset,
+		closeLogConn,
+		nil,
+	)
+
+	allLogs := ""
+	for l := range logsChan {
+		allLogs += string(l.Data)
+	}
+	return toLogRows(allLogs, redactions)
+}
+
+func toLogRows(logs string, redactions []*regexp.Regexp) []string {
+	// redact before anything is displayed, saved or copied
+	logs = formatter.Redact(logs, redactions, constants.LogRedactionReplacement)
+	trimmedBody := strings.ReplaceAll(logs, "\t", "    ")
+	return strings.Split(formatter.StripANSI(trimmedBody), "\n")
+}
+
+// LogsStream follows a task's logs from the end as they're written. Only its ID is set until it's connected.
+type LogsStream struct {
+	ID     int
+	frames <-chan *api.StreamFrame
+	errs   <-chan error
+	cancel context.CancelFunc
+}
+
+// Close stops following the logs
+func (s LogsStream) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+type LogsFollowedMsg struct {
+	Stream LogsStream
+}
+
+// LogsStreamMsg is the lines written to followed logs since the last. Partial is the start of a line not yet ended,
+// which is passed to the next read. Done is set when the stream ends, e.g. as the task stopped, with Err if it failed.
+type LogsStreamMsg struct {
+	ID      int
+	Rows    []page.Row
+	Partial string
+	Done    bool
+	Err     error
+}
+
+// FollowLogs connects to the task's logs to stream what's written from now on
+func FollowLogs(client api.Client, alloc api.Allocation, taskName string, logType LogType, id int) tea.Cmd {
+	return func() tea.Msg {
+		// see readLogRows
+		api.ClientConnTimeout = 1 * time.Microsecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		q := (&api.QueryOptions{}).WithContext(ctx)
+		frames, errs := client.AllocFS().Logs(&alloc, true, taskName, logType.ShortString(), "end", 0, ctx.Done(), q)
+		return LogsFollowedMsg{Stream: LogsStream{ID: id, frames: frames, errs: errs, cancel: cancel}}
+	}
+}
+
+// ReadLogsStream waits for more of the followed logs, then batches whatever else arrives within coalesce so bursts of
+// output render once rather than per line. A coalesce of 0 only batches what has already arrived, adding no latency.
+func ReadLogsStream(s LogsStream, partial string, coalesce time.Duration, redactions []*regexp.Regexp, prettyJSON bool, maxLineLength int) tea.Cmd {
+	return func() tea.Msg {
+		var data strings.Builder
+		data.WriteString(partial)
+		msg := LogsStreamMsg{ID: s.ID}
+
+		select {
+		case frame, ok := <-s.frames:
+			if ok {
+				data.Write(frame.Data)
+			} else {
+				msg.Done = true
+			}
+		case msg.Err = <-s.errs:
+			msg.Done = true
+		}
+
+		var deadline <-chan time.Time
+		if coalesce > 0 {
+			deadline = time.After(coalesce)
+		}
+	batch:
+		for !msg.Done {
+			var frame *api.StreamFrame
+			var ok bool
+			if deadline == nil {
+				select {
+				case frame, ok = <-s.frames:
+				case msg.Err = <-s.errs:
+					msg.Done = true
+					continue
+				default:
+					break batch
+				}
+			} else {
+				select {
+				case frame, ok = <-s.frames:
+				case msg.Err = <-s.errs:
+					msg.Done = true
+					continue
+				case <-deadline:
+					break batch
+				}
+			}
+			if !ok {
+				msg.Done = true
+				continue
+			}
+			data.Write(frame.Data)
+		}
+
+		// hold back a line that hasn't ended yet unless there won't be more of it
+		complete := data.String()
+		if idx := strings.LastIndex(complete, "\n"); !msg.Done {
+			complete, msg.Partial = complete[:idx+1], complete[idx+1:]
+		}
+		if complete != "" {
+			msg.Rows = logPageRows(toLogLines(toLogRows(complete, redactions), prettyJSON, ""), maxLineLength)
+		}
+		return msg
+	}
+}
+
+type mergedLogLine struct {
+	// Source is the allocation ID or task name the line was logged by
+	Source string
+	Index  int
+	Time   time.Time
+	Line   string
+}
+
+func toMergedLogLines(source string, logRows []string) []mergedLogLine {
+	var lines []mergedLogLine
+	var lastTime time.Time
+	for idx, row := range logRows {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		if t, ok := parseLogTimestamp(row); ok {
+			lastTime = t
+		}
+		lines = append(lines, mergedLogLine{Source: source, Index: idx, Time: lastTime, Line: row})
+	}
+	return lines
+}
+
+// sortMergedLogLines orders lines by time, keeping the order of lines from the same source
+func sortMergedLogLines(lines []mergedLogLine) {
+	sort.SliceStable(lines, func(x, y int) bool {
+		if lines[x].Time.Equal(lines[y].Time) {
+			if lines[x].Source == lines[y].Source {
+				return lines[x].Index < lines[y].Index
+			}
+			return lines[x].Source < lines[y].Source
+		}
+		return lines[x].Time.Before(lines[y].Time)
+	})
+}
+
+var logTimestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+
+// parseLogTimestamp parses a timestamp at the start of a log line, optionally in brackets
+func parseLogTimestamp(line string) (time.Time, bool) {
+	fields := strings.Fields(strings.TrimLeft(line, "["))
+	candidates := []string{}
+	if len(fields) > 0 {
+		candidates = append(candidates, strings.TrimRight(fields[0], "]"))
+	}
+	if len(fields) > 1 {
+		candidates = append(candidates, strings.TrimRight(fields[0]+" "+fields[1], "]"))
+	}
+	for _, c := range candidates {
+		for _, layout := range logTimestampLayouts {
+			if t, err := time.Parse(layout, c); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+type logLine struct {
+	text string
+	json bool
+}
+
+// toLogLines prefixes each log row, expanding rows that are JSON objects or arrays into indented lines if prettyJSON.
+// Rows that aren't valid JSON are left intact.
+func toLogLines(logRows []string, prettyJSON bool, prefix string) []logLine {
+	var lines []logLine
+	for _, row := range logRows {
+		trimmed := strings.TrimSpace(row)
+		isJSON := strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+		if prettyJSON && isJSON && json.Valid([]byte(trimmed)) {
+			for _, l := range formatter.PrettyJsonStringAsLines(trimmed) {
+				lines = append(lines, logLine{text: prefix + l, json: true})
+			}
+			continue
+		}
+		lines = append(lines, logLine{text: prefix + row})
+	}
+	return lines
+}
+
+func logsColumn(logType LogType, logOffset int, fromStart, fellBack bool) string {
+	if fromStart {
+		return fmt.Sprintf("%s (from start)", logType.String())
+	}
+	if fellBack {
+		return fmt.Sprintf("%s (from start, none in last %d bytes)", logType.String(), logOffset)
+	}
+	return logType.String()
+}
+
+// logsAsTable truncates lines over maxLineLength bytes, as the table pads every row to the longest, keeping the full
+// line in the row to save or view
+func logsAsTable(logs []logLine, column string, maxLineLength int) ([]string, []page.Row) {
 	var logRows [][]string
-	var keys []string
-	for _, row := range logs {
-		if stripped := strings.TrimSpace(row); stripped != "" {
-			logRows = append(logRows, []string{row})
+	var jsonRows []bool
+	var fullRows []string
+	for _, l := range logs {
+		if stripped := strings.TrimSpace(l.text); stripped != "" {
+			shown, truncated := TruncateLogLine(l.text, maxLineLength)
+			var full string
+			if truncated {
+				full = l.text
+			}
+			logRows = append(logRows, []string{shown})
+			jsonRows = append(jsonRows, l.json)
+			fullRows = append(fullRows, full)
 		}
-		keys = append(keys, "")
 	}
 
-	columns := []string{logType.String()}
-	table := formatter.GetRenderedTableAsString(columns, logRows)
+	table := formatter.GetRenderedTableAsString([]string{column}, logRows)
 
 	var rows []page.Row
 	for idx, row := range table.ContentRows {
-		rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		r := page.Row{Key: "", Row: row, Full: fullRows[idx]}
+		if jsonRows[idx] {
+			r.Style = &style.JSONLog
+		}
+		rows = append(rows, r)
 	}
 
 	return table.HeaderRows, rows
 }
+
+// logPageRows are the rows of logs appended to the logs page, truncated like logsAsTable
+func logPageRows(logs []logLine, maxLineLength int) []page.Row {
+	var rows []page.Row
+	for _, l := range logs {
+		if strings.TrimSpace(l.text) == "" {
+			continue
+		}
+		shown, truncated := TruncateLogLine(l.text, maxLineLength)
+		r := page.Row{Key: "", Row: shown}
+		if truncated {
+			r.Full = l.text
+		}
+		if l.json {
+			r.Style = &style.JSONLog
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// TruncateLogLine cuts line to maxLength bytes, on a character boundary, marking it as truncated. A maxLength of 0
+// doesn't truncate.
+func TruncateLogLine(line string, maxLength int) (string, bool) {
+	if maxLength <= 0 || len(line) <= maxLength {
+		return line, false
+	}
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return line[:cut] + constants.TruncatedLogLineMarker, true
+}
+
+// FetchLogline shows a log line, pretty printed if it's JSON. Other lines over maxLength bytes are truncated, but saved
+// in full.
+func FetchLogline(line string, maxLength int) tea.Cmd {
+	if json.Valid([]byte(strings.TrimSpace(line))) {
+		return PrettifyLine(line, LoglinePage)
+	}
+	return func() tea.Msg {
+		shown, truncated := TruncateLogLine(line, maxLength)
+		row := page.Row{Key: "", Row: shown}
+		if truncated {
+			row.Full = line
+		}
+		return PageLoadedMsg{Page: LoglinePage, TableHeader: []string{}, AllPageRows: []page.Row{row}}
+	}
+}
@@ -0,0 +1,125 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strings"
+)
+
+type NamespaceCycledMsg struct {
+	Namespace string
+}
+
+// CycleNamespace moves step namespaces on from current in the cluster's namespaces, sorted, with "*" for all namespaces
+// first. It wraps around, and the list is fetched each time so new namespaces are included.
+func CycleNamespace(client api.Client, current string, step int) tea.Cmd {
+	return func() tea.Msg {
+		namespaces, _, err := client.Namespaces().List(nil)
+		if err != nil {
+			return message.ToastMsg{Err: fmt.Errorf("could not list namespaces: %w", err)}
+		}
+		var names []string
+		for _, n := range namespaces {
+			names = append(names, n.Name)
+		}
+		sort.Strings(names)
+		names = append([]string{"*"}, names...)
+
+		idx := -1
+		for i, n := range names {
+			if n == current {
+				idx = i
+			}
+		}
+		if idx == -1 && step < 0 {
+			idx = 0
+		}
+		next := ((idx+step)%len(names) + len(names)) % len(names)
+		return NamespaceCycledMsg{Namespace: names[next]}
+	}
+}
+
+// TokenNamespaceMsg has the one namespace the token's policies grant, or is empty if they grant more, any or none
+type TokenNamespaceMsg struct {
+	Namespace string
+}
+
+// aclPolicyRules is the namespace blocks of an ACL policy's rules, which are HCL or JSON
+type aclPolicyRules struct {
+	Namespaces []*aclNamespaceRules `hcl:"namespace,expand"`
+}
+
+type aclNamespaceRules struct {
+	Name         string   `hcl:",key"`
+	Policy       string   `hcl:"policy"`
+	Capabilities []string `hcl:"capabilities"`
+}
+
+// grantsAccess is false for namespace blocks that deny access, or grant nothing
+func (r aclNamespaceRules) grantsAccess() bool {
+	if r.Policy == "deny" {
+		return false
+	}
+	for _, c := range r.Capabilities {
+		if c == "deny" {
+			return false
+		}
+	}
+	return r.Policy != "" || len(r.Capabilities) > 0
+}
+
+// policyNamespaces are the namespaces the rules of an ACL policy grant access to
+func policyNamespaces(rules string) ([]string, error) {
+	var parsed aclPolicyRules
+	if err := hcl.Decode(&parsed, rules); err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, n := range parsed.Namespaces {
+		if n.grantsAccess() {
+			namespaces = append(namespaces, n.Name)
+		}
+	}
+	return namespaces, nil
+}
+
+// FetchTokenNamespace finds the namespace a token is scoped to from the namespace blocks in the rules of its policies,
+// which a token can read. Management tokens, globs like "dev-*", and failures to read the token or parse its policies
+// leave the namespace as configured.
+func FetchTokenNamespace(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		self, _, err := client.ACLTokens().Self(nil)
+		if err != nil || self.Type == "management" {
+			return TokenNamespaceMsg{}
+		}
+		namespaces := make(map[string]bool)
+		for _, name := range self.Policies {
+			policy, _, err := client.ACLPolicies().Info(name, nil)
+			if err != nil {
+				return TokenNamespaceMsg{}
+			}
+			granted, err := policyNamespaces(policy.Rules)
+			if err != nil {
+				return TokenNamespaceMsg{}
+			}
+			for _, n := range granted {
+				namespaces[n] = true
+			}
+		}
+		if len(namespaces) != 1 {
+			return TokenNamespaceMsg{}
+		}
+		var namespace string
+		for n := range namespaces {
+			namespace = n
+		}
+		if strings.Contains(namespace, "*") {
+			return TokenNamespaceMsg{}
+		}
+		return TokenNamespaceMsg{Namespace: namespace}
+	}
+}
@@ -0,0 +1,60 @@
+package nomad
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    string
+		expected []string
+	}{
+		{
+			"hcl",
+			`namespace "dev" { policy = "write" }
+node { policy = "read" }`,
+			[]string{"dev"},
+		},
+		{
+			"commented out",
+			`# namespace "prod" { policy = "read" }
+/* namespace "staging" { policy = "read" } */
+namespace "dev" { capabilities = ["read-job"] }`,
+			[]string{"dev"},
+		},
+		{
+			"denied",
+			`namespace "dev" { policy = "read" }
+namespace "prod" { policy = "deny" }
+namespace "staging" { capabilities = ["deny"] }`,
+			[]string{"dev"},
+		},
+		{
+			"json",
+			`{"namespace": {"dev": {"policy": "read"}, "prod": {"policy": "deny"}}}`,
+			[]string{"dev"},
+		},
+		{
+			"no namespaces",
+			`agent { policy = "read" }`,
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := policyNamespaces(test.rules)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+
+	if _, err := policyNamespaces(`namespace "dev" {`); err == nil {
+		t.Error("expected an error for invalid rules")
+	}
+}
@@ -0,0 +1,81 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"strings"
+)
+
+func FetchNodeSpec(client api.Client, nodeID string) tea.Cmd {
+	return func() tea.Msg {
+		node, _, err := client.Nodes().Info(nodeID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		nodeBytes, err := json.Marshal(node)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		pretty := formatter.PrettyJsonStringAsLines(string(nodeBytes))
+
+		var nodeSpecPageData []page.Row
+		for _, row := range pretty {
+			nodeSpecPageData = append(nodeSpecPageData, page.Row{Key: "", Row: row})
+		}
+
+		return PageLoadedMsg{
+			Page:        NodeSpecPage,
+			TableHeader: nodeSummary(node),
+			AllPageRows: nodeSpecPageData,
+		}
+	}
+}
+
+// nodeSummary explains why a node may not be receiving allocations: its status, eligibility, drain and latest event
+func nodeSummary(node *api.Node) []string {
+	status := "Status: " + node.Status
+	if node.StatusDescription != "" {
+		status += fmt.Sprintf(" (%s)", node.StatusDescription)
+	}
+	summary := []string{status + ", scheduling " + node.SchedulingEligibility}
+
+	if d := node.DrainStrategy; d != nil {
+		drain := fmt.Sprintf("Draining since %s", formatter.FormatTime(d.StartedAt))
+		// Nomad uses a negative deadline for a forced drain, and no deadline for one that waits on allocations
+		switch {
+		case d.Deadline < 0:
+			drain += ", forced"
+		case d.Deadline == 0:
+			drain += ", no deadline"
+		default:
+			drain += fmt.Sprintf(", deadline %s (forced at %s)", d.Deadline, formatter.FormatTime(d.ForceDeadline))
+		}
+		if d.IgnoreSystemJobs {
+			drain += ", ignoring system jobs"
+		}
+		summary = append(summary, drain)
+	} else if node.LastDrain != nil {
+		summary = append(summary, fmt.Sprintf("Last drain %s at %s", node.LastDrain.Status, formatter.FormatTime(node.LastDrain.UpdatedAt)))
+	}
+
+	if len(node.Events) > 0 {
+		var last *api.NodeEvent
+		for _, e := range node.Events {
+			if last == nil || e.Timestamp.After(last.Timestamp) {
+				last = e
+			}
+		}
+		event := fmt.Sprintf("Last event: %s at %s", last.Message, formatter.FormatTime(last.Timestamp))
+		if last.Subsystem != "" {
+			event += fmt.Sprintf(" (%s)", strings.ToLower(last.Subsystem))
+		}
+		summary = append(summary, event)
+	}
+	return summary
+}
@@ -32,15 +32,41 @@ const (
 	AllocSpecPage
 	LogsPage
 	LoglinePage
+	SearchPage
+	NodeSpecPage
+	DispatchPage
+	EvaluationPage
+	ACLPoliciesPage
+	ACLPolicyPage
+	TaskEventsPage
+	TaskEventPage
+	TopPage
+	JobPlacementPage
+	JobCoveragePage
+	AllocPortsPage
+	JobEvaluationsPage
+	JobDeploymentPage
+	AllocVolumesPage
+	AllocStatsPage
+	JobRolloutPage
+	AllocChecksPage
+	ErrorsPage
+	RawResponsesPage
+	AboutPage
+	ServersPage
+	QuotasPage
 )
 
-func GetAllPageConfigs(width, height int, copySavePath bool) map[Page]page.Config {
+// GetAllPageConfigs configures every page. Pages showing tables are limited to maxTableRows rows if it's above 0.
+func GetAllPageConfigs(width, height int, copySavePath bool, highlightRules []page.HighlightRule, maxTableRows int) map[Page]page.Config {
 	return map[Page]page.Config{
 		JobsPage: {
 			Width: width, Height: height,
 			FilterPrefix: "Jobs", LoadingString: JobsPage.LoadingString(),
 			CopySavePath: copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
 			ViewportConditionalStyle: constants.JobsViewportConditionalStyle,
+			HighlightRules:           highlightRules,
+			MaxRows:                  maxTableRows,
 		},
 		JobSpecPage: {
 			Width: width, Height: height,
@@ -82,6 +108,8 @@ func GetAllPageConfigs(width, height int, copySavePath bool) map[Page]page.Confi
 			LoadingString: AllocationsPage.LoadingString(),
 			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
 			ViewportConditionalStyle: constants.AllocationsViewportConditionalStyle,
+			HighlightRules:           highlightRules,
+			MaxRows:                  maxTableRows,
 		},
 		ExecPage: {
 			Width: width, Height: height,
@@ -97,17 +125,157 @@ func GetAllPageConfigs(width, height int, copySavePath bool) map[Page]page.Confi
 			Width: width, Height: height,
 			LoadingString: LogsPage.LoadingString(),
 			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+			PinningEnabled: true,
 		},
 		LoglinePage: {
 			Width: width, Height: height,
 			LoadingString: LoglinePage.LoadingString(),
 			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
 		},
+		SearchPage: {
+			Width: width, Height: height,
+			LoadingString: SearchPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		NodeSpecPage: {
+			Width: width, Height: height,
+			LoadingString: NodeSpecPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		DispatchPage: {
+			Width: width, Height: height,
+			LoadingString: DispatchPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: true,
+		},
+		EvaluationPage: {
+			Width: width, Height: height,
+			LoadingString: EvaluationPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		ACLPoliciesPage: {
+			Width: width, Height: height,
+			LoadingString: ACLPoliciesPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		ACLPolicyPage: {
+			Width: width, Height: height,
+			LoadingString: ACLPolicyPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		TaskEventsPage: {
+			Width: width, Height: height,
+			LoadingString: TaskEventsPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		TaskEventPage: {
+			Width: width, Height: height,
+			LoadingString: TaskEventPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		TopPage: {
+			Width: width, Height: height,
+			LoadingString: TopPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		JobPlacementPage: {
+			Width: width, Height: height,
+			LoadingString: JobPlacementPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		JobCoveragePage: {
+			Width: width, Height: height,
+			LoadingString: JobCoveragePage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		AllocPortsPage: {
+			Width: width, Height: height,
+			LoadingString: AllocPortsPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		JobEvaluationsPage: {
+			Width: width, Height: height,
+			LoadingString: JobEvaluationsPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		JobDeploymentPage: {
+			Width: width, Height: height,
+			LoadingString: JobDeploymentPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: true, RequestInput: false,
+		},
+		AllocVolumesPage: {
+			Width: width, Height: height,
+			LoadingString: AllocVolumesPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		AllocStatsPage: {
+			Width: width, Height: height,
+			LoadingString: AllocStatsPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		JobRolloutPage: {
+			Width: width, Height: height,
+			LoadingString: JobRolloutPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		AllocChecksPage: {
+			Width: width, Height: height,
+			LoadingString: AllocChecksPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		ErrorsPage: {
+			Width: width, Height: height,
+			LoadingString: ErrorsPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: true, RequestInput: false,
+		},
+		RawResponsesPage: {
+			Width: width, Height: height,
+			LoadingString: RawResponsesPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: false, RequestInput: false,
+		},
+		AboutPage: {
+			Width: width, Height: height,
+			LoadingString: AboutPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: true, WrapText: true, RequestInput: false,
+		},
+		ServersPage: {
+			Width: width, Height: height,
+			LoadingString: ServersPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
+		QuotasPage: {
+			Width: width, Height: height,
+			LoadingString: QuotasPage.LoadingString(),
+			CopySavePath:  copySavePath, SelectionEnabled: false, WrapText: false, RequestInput: false,
+			MaxRows: maxTableRows,
+		},
 	}
 }
 
+// SortFields are the fields the page's rows can be sorted by in config, or nil if it can't be
+func (p Page) SortFields() []string {
+	switch p {
+	case JobsPage:
+		return []string{"id", "name", "type", "namespace", "datacenters", "priority", "status", "submitted"}
+	case AllocationsPage:
+		return []string{"id", "task_group", "name", "task", "state", "datacenter", "started", "finished"}
+	}
+	return nil
+}
+
 func (p Page) DoesLoad() bool {
-	noLoadPages := []Page{LoglinePage, JobEventPage, AllocEventPage, AllEventPage}
+	noLoadPages := []Page{LoglinePage, JobEventPage, AllocEventPage, AllEventPage, TaskEventPage}
 	for _, noLoadPage := range noLoadPages {
 		if noLoadPage == p {
 			return false
@@ -117,7 +285,7 @@ func (p Page) DoesLoad() bool {
 }
 
 func (p Page) DoesReload() bool {
-	noReloadPages := []Page{LoglinePage, JobEventsPage, JobEventPage, AllocEventsPage, AllocEventPage, AllEventsPage, AllEventPage, ExecPage}
+	noReloadPages := []Page{LoglinePage, JobEventsPage, JobEventPage, AllocEventsPage, AllocEventPage, AllEventsPage, AllEventPage, ExecPage, DispatchPage, EvaluationPage, TaskEventPage}
 	for _, noReloadPage := range noReloadPages {
 		if noReloadPage == p {
 			return false
@@ -126,19 +294,38 @@ func (p Page) DoesReload() bool {
 	return true
 }
 
+// IsJobScoped is true for pages showing a single job or its allocations, i.e. in that job's namespace
+func (p Page) IsJobScoped() bool {
+	jobScopedPages := []Page{JobSpecPage, JobEventsPage, JobEventPage, AllocEventsPage, AllocEventPage, AllocationsPage, ExecPage, AllocSpecPage, LogsPage, LoglinePage, DispatchPage, TaskEventsPage, TaskEventPage, JobPlacementPage, JobCoveragePage, AllocPortsPage, JobEvaluationsPage, JobDeploymentPage, AllocVolumesPage, AllocStatsPage, JobRolloutPage, AllocChecksPage}
+	for _, jobScopedPage := range jobScopedPages {
+		if jobScopedPage == p {
+			return true
+		}
+	}
+	return false
+}
+
 func (p Page) doesUpdate() bool {
 	noUpdatePages := []Page{
-		LoglinePage,     // doesn't load
-		ExecPage,        // doesn't reload
-		LogsPage,        // currently makes scrolling impossible - solve in https://github.com/robinovitch61/wander/issues/1
-		JobSpecPage,     // would require changes to make scrolling possible
-		AllocSpecPage,   // would require changes to make scrolling possible
-		JobEventsPage,   // constant connection, streams data
-		JobEventPage,    // doesn't load
-		AllocEventsPage, // constant connection, streams data
-		AllocEventPage,  // doesn't load
-		AllEventsPage,   // constant connection, streams data
-		AllEventPage,    // doesn't load
+		LoglinePage,      // doesn't load
+		ExecPage,         // doesn't reload
+		LogsPage,         // follows the logs as they're written
+		JobSpecPage,      // would require changes to make scrolling possible
+		AllocSpecPage,    // would require changes to make scrolling possible
+		JobEventsPage,    // constant connection, streams data
+		JobEventPage,     // doesn't load
+		AllocEventsPage,  // constant connection, streams data
+		AllocEventPage,   // doesn't load
+		AllEventsPage,    // constant connection, streams data
+		AllEventPage,     // doesn't load
+		SearchPage,       // reloads as the search query changes
+		NodeSpecPage,     // would require changes to make scrolling possible
+		DispatchPage,     // doesn't reload
+		EvaluationPage,   // polls until the evaluation completes
+		ACLPolicyPage,    // would require changes to make scrolling possible
+		TaskEventPage,    // doesn't load
+		JobPlacementPage, // would require changes to make scrolling possible
+		AboutPage,        // doesn't change while connected
 	}
 	for _, noUpdatePage := range noUpdatePages {
 		if noUpdatePage == p {
@@ -172,6 +359,52 @@ func (p Page) String() string {
 		return "logs"
 	case LoglinePage:
 		return "log"
+	case SearchPage:
+		return "search"
+	case NodeSpecPage:
+		return "node spec"
+	case DispatchPage:
+		return "dispatch"
+	case EvaluationPage:
+		return "evaluation"
+	case ACLPoliciesPage:
+		return "acl policies"
+	case ACLPolicyPage:
+		return "acl policy"
+	case TaskEventsPage:
+		return "task events"
+	case TaskEventPage:
+		return "task event"
+	case TopPage:
+		return "top"
+	case JobPlacementPage:
+		return "placement"
+	case JobCoveragePage:
+		return "coverage"
+	case AllocPortsPage:
+		return "ports"
+	case JobEvaluationsPage:
+		return "evaluations"
+	case JobDeploymentPage:
+		return "deployment"
+	case AllocVolumesPage:
+		return "volumes"
+	case AllocStatsPage:
+		return "stats"
+	case JobRolloutPage:
+		return "rollout"
+	case AllocChecksPage:
+		return "checks"
+	case ErrorsPage:
+		return "errors"
+	case RawResponsesPage:
+		return "responses"
+	case AboutPage:
+		return "about"
+	case ServersPage:
+		return "servers"
+	case QuotasPage:
+		return "quotas"
 	}
 	return "unknown"
 }
@@ -194,6 +427,12 @@ func (p Page) Forward() Page {
 		return LogsPage
 	case LogsPage:
 		return LoglinePage
+	case ACLPoliciesPage:
+		return ACLPolicyPage
+	case TaskEventsPage:
+		return TaskEventPage
+	case JobEvaluationsPage:
+		return EvaluationPage
 	}
 	return p
 }
@@ -224,11 +463,61 @@ func (p Page) Backward() Page {
 		return AllocationsPage
 	case LoglinePage:
 		return LogsPage
+	case SearchPage:
+		return JobsPage
+	case NodeSpecPage:
+		return SearchPage
+	case DispatchPage:
+		return JobsPage
+	case EvaluationPage:
+		// the app returns to the page the evaluation was followed from
+		return JobsPage
+	case ACLPoliciesPage:
+		return JobsPage
+	case ACLPolicyPage:
+		return ACLPoliciesPage
+	case TaskEventsPage:
+		return AllocationsPage
+	case TaskEventPage:
+		return TaskEventsPage
+	case TopPage:
+		return JobsPage
+	case ServersPage:
+		return JobsPage
+	case QuotasPage:
+		return JobsPage
+	case JobPlacementPage:
+		return JobSpecPage
+	case JobCoveragePage:
+		return AllocationsPage
+	case AllocPortsPage:
+		return AllocationsPage
+	case JobEvaluationsPage:
+		return AllocationsPage
+	case JobDeploymentPage:
+		return AllocationsPage
+	case AllocVolumesPage:
+		return AllocationsPage
+	case AllocStatsPage:
+		return AllocationsPage
+	case JobRolloutPage:
+		return AllocationsPage
+	case AllocChecksPage:
+		return AllocationsPage
+	case ErrorsPage:
+		// the app returns to the page the errors were opened from
+		return JobsPage
+	case RawResponsesPage:
+		// the app returns to the page the responses were opened from
+		return JobsPage
+	case AboutPage:
+		// the app returns to the page about was opened from
+		return JobsPage
 	}
 	return p
 }
 
-func (p Page) GetFilterPrefix(jobID, taskName, allocID string, eventTopics Topics, eventNamespace string) string {
+func (p Page) GetFilterPrefix(jobID, taskName, allocID, nodeID, evalID, aclPolicyName string, eventTopics Topics, eventNamespace string) string {
 	switch p {
 	case JobsPage:
 		return "Jobs"
@@ -256,6 +545,52 @@ func (p Page) GetFilterPrefix(jobID, taskName, allocID string, eventTopics Topic
 		return fmt.Sprintf("Logs for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
 	case LoglinePage:
 		return fmt.Sprintf("Log Line for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case SearchPage:
+		return "Search Jobs, Allocations & Nodes by ID Prefix"
+	case NodeSpecPage:
+		return fmt.Sprintf("Node Spec for %s", style.Bold.Render(formatter.ShortAllocID(nodeID)))
+	case DispatchPage:
+		return fmt.Sprintf("Dispatch %s", style.Bold.Render(jobID))
+	case EvaluationPage:
+		return fmt.Sprintf("Evaluation %s", style.Bold.Render(formatter.ShortAllocID(evalID)))
+	case ACLPoliciesPage:
+		return "ACL Policies"
+	case ACLPolicyPage:
+		return fmt.Sprintf("ACL Policy %s", style.Bold.Render(aclPolicyName))
+	case TaskEventsPage:
+		return fmt.Sprintf("Task Events for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case TaskEventPage:
+		return fmt.Sprintf("Task Event for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case TopPage:
+		return "Cluster Resources Allocated"
+	case ServersPage:
+		return "Server Members"
+	case QuotasPage:
+		return "Quota Usage"
+	case ErrorsPage:
+		return "Recent Errors"
+	case RawResponsesPage:
+		return "Raw API Responses, newest first"
+	case AboutPage:
+		return "About wander and its connection"
+	case JobPlacementPage:
+		return fmt.Sprintf("Placement Rules for %s", style.Bold.Render(jobID))
+	case JobCoveragePage:
+		return fmt.Sprintf("Node Coverage for %s", style.Bold.Render(jobID))
+	case JobDeploymentPage:
+		return fmt.Sprintf("Latest Deployment for %s", style.Bold.Render(jobID))
+	case JobRolloutPage:
+		return fmt.Sprintf("Rollout of %s", style.Bold.Render(jobID))
+	case JobEvaluationsPage:
+		return fmt.Sprintf("Recent Evaluations for %s", style.Bold.Render(jobID))
+	case AllocPortsPage:
+		return fmt.Sprintf("Ports for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case AllocVolumesPage:
+		return fmt.Sprintf("Volumes for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case AllocStatsPage:
+		return fmt.Sprintf("Stats for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
+	case AllocChecksPage:
+		return fmt.Sprintf("Service Checks for %s %s", style.Bold.Render(taskName), formatter.ShortAllocID(allocID))
 	default:
 		panic("page not found")
 	}
@@ -272,6 +607,10 @@ type PageLoadedMsg struct {
 	TableHeader []string
 	AllPageRows []page.Row
 	Connection  EventsStream
+	// MoreAvailable is set for pages loaded incrementally that have more to fetch
+	MoreAvailable bool
+	// FetchID identifies the request for pages whose responses to earlier requests are dropped
+	FetchID int
 }
 
 type UpdatePageDataMsg struct {
@@ -299,15 +638,64 @@ func changeKeyHelp(k *key.Binding, h string) {
 	k.SetHelp(k.Help().Key, h)
 }
 
-func GetPageKeyHelp(currentPage Page, filterFocused, filterApplied, saving, enteringInput, inPty, webSocketConnected bool, logType LogType) string {
+// KeyHelpState is the state of the app that decides which key bindings are shown for a page
+type KeyHelpState struct {
+	FilterFocused          bool
+	FilterApplied          bool
+	Saving                 bool
+	EnteringInput          bool
+	InPty                  bool
+	WebSocketConnected     bool
+	ReadOnly               bool
+	ACLReadable            bool
+	QuotasAvailable        bool
+	LogsMerged             bool
+	LogsAllTasks           bool
+	LogsPrettyJSON         bool
+	LogsFromStart          bool
+	EventsPaused           bool
+	MultipleEventJQQueries bool
+	EventsFilterRegex      bool
+	DeadJobsHidden         bool
+	TaskGroupsCollapsed    bool
+	CapturingResponses     bool
+	LogType                LogType
+	FooterHints            []key.Binding
+}
+
+func GetPageKeyHelp(currentPage Page, state KeyHelpState) string {
+	var final string
+	for _, row := range GetPageKeyBindings(currentPage, state) {
+		final += getShortHelp(row) + "\n"
+	}
+	return strings.TrimRight(final, "\n")
+}
+
+// GetPageKeyBindings are the rows of key bindings available on the page in its current state, as shown in the header
+func GetPageKeyBindings(currentPage Page, state KeyHelpState) [][]key.Binding {
 	firstRow := []key.Binding{keymap.KeyMap.Exit}
 
-	if currentPage.DoesReload() && !saving && !filterFocused {
+	if currentPage.DoesReload() && !state.Saving && !state.FilterFocused {
 		firstRow = append(firstRow, keymap.KeyMap.Reload)
 	}
 
+	if currentPage != ErrorsPage {
+		firstRow = append(firstRow, keymap.KeyMap.Errors)
+	}
+
+	if state.CapturingResponses && currentPage != RawResponsesPage {
+		firstRow = append(firstRow, keymap.KeyMap.RawResponses)
+	}
+
+	if currentPage != AboutPage {
+		firstRow = append(firstRow, keymap.KeyMap.About)
+	}
+
 	viewportKeyMap := viewport.GetKeyMap()
-	secondRow := []key.Binding{viewportKeyMap.Save, keymap.KeyMap.Wrap}
+	secondRow := []key.Binding{viewportKeyMap.Save, keymap.KeyMap.HTMLSnapshot, keymap.KeyMap.Wrap}
+	if IsLinkable(currentPage) {
+		secondRow = append(secondRow, keymap.KeyMap.CopyLink)
+	}
 	thirdRow := []key.Binding{viewportKeyMap.Down, viewportKeyMap.Up, viewportKeyMap.PageDown, viewportKeyMap.PageUp}
 
 	var fourthRow []key.Binding
@@ -316,7 +704,7 @@ func GetPageKeyHelp(currentPage Page, filterFocused, filterApplied, saving, ente
 		fourthRow = append(fourthRow, keymap.KeyMap.Forward)
 	}
 
-	if filterApplied {
+	if state.FilterApplied {
 		changeKeyHelp(&keymap.KeyMap.Back, "remove filter")
 		fourthRow = append(fourthRow, keymap.KeyMap.Back)
 	} else if prevPage := currentPage.Backward(); prevPage != currentPage {
@@ -327,59 +715,192 @@ func GetPageKeyHelp(currentPage Page, filterFocused, filterApplied, saving, ente
 	if currentPage == JobsPage || currentPage == AllocationsPage {
 		fourthRow = append(fourthRow, keymap.KeyMap.Spec)
 	} else if currentPage == LogsPage {
-		if logType == StdOut {
+		if state.LogType == StdOut {
 			fourthRow = append(fourthRow, keymap.KeyMap.StdErr)
 		} else {
 			fourthRow = append(fourthRow, keymap.KeyMap.StdOut)
 		}
+		if state.LogsMerged {
+			changeKeyHelp(&keymap.KeyMap.MergeLogs, "per alloc")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.MergeLogs, "merge allocs")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.MergeLogs)
+		if state.LogsAllTasks {
+			changeKeyHelp(&keymap.KeyMap.AllTaskLogs, "single task")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.AllTaskLogs, "all tasks")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.AllTaskLogs)
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyLogPath)
+		fourthRow = append(fourthRow, keymap.KeyMap.OpenInPager)
+		if state.LogsFromStart {
+			changeKeyHelp(&keymap.KeyMap.FromStart, "from end")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.FromStart, "from start")
+			fourthRow = append(fourthRow, keymap.KeyMap.OlderLogs)
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.FromStart)
+		if state.LogsPrettyJSON {
+			changeKeyHelp(&keymap.KeyMap.PrettyJSON, "raw json")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.PrettyJSON, "pretty json")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.PrettyJSON)
+		thirdRow = append(thirdRow, viewportKeyMap.Pin, viewportKeyMap.NextPin)
 	}
 
 	if currentPage == JobsPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyID)
 		fourthRow = append(fourthRow, keymap.KeyMap.JobEvents)
 		fourthRow = append(fourthRow, keymap.KeyMap.AllEvents)
+		fourthRow = append(fourthRow, keymap.KeyMap.Search)
+		fourthRow = append(fourthRow, keymap.KeyMap.Top)
+		fourthRow = append(fourthRow, keymap.KeyMap.Servers)
+		if state.DeadJobsHidden {
+			changeKeyHelp(&keymap.KeyMap.HideDead, "show dead")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.HideDead, "hide dead")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.HideDead)
+		fourthRow = append(fourthRow, keymap.KeyMap.JobType)
+		fourthRow = append(fourthRow, keymap.KeyMap.PrevNS, keymap.KeyMap.NextNS)
+		if state.ACLReadable {
+			fourthRow = append(fourthRow, keymap.KeyMap.ACLPolicies)
+		}
+		if state.QuotasAvailable {
+			fourthRow = append(fourthRow, keymap.KeyMap.Quotas)
+		}
+	}
+
+	if currentPage == SearchPage {
+		changeKeyHelp(&keymap.KeyMap.Forward, "go to result")
+		fourthRow = append([]key.Binding{keymap.KeyMap.Forward}, fourthRow...)
+	}
+
+	if currentPage == JobSpecPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.Placement)
+		fourthRow = append(fourthRow, keymap.KeyMap.JobSource)
+	}
+
+	if (currentPage == JobsPage || currentPage == JobSpecPage) && !state.ReadOnly {
+		fourthRow = append(fourthRow, keymap.KeyMap.Edit)
+	}
+
+	if currentPage == JobsPage && !state.ReadOnly {
+		fourthRow = append(fourthRow, keymap.KeyMap.Dispatch)
+		fourthRow = append(fourthRow, keymap.KeyMap.RestartAll)
+	}
+
+	if (currentPage == JobsPage || currentPage == AllocationsPage) && !state.ReadOnly {
+		fourthRow = append(fourthRow, keymap.KeyMap.Reevaluate)
 	}
 
 	if currentPage == AllocationsPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyID, keymap.KeyMap.CopyShortID)
 		fourthRow = append(fourthRow, keymap.KeyMap.AllocEvents)
+		fourthRow = append(fourthRow, keymap.KeyMap.TaskEvents)
+		changeKeyHelp(&keymap.KeyMap.AllTaskLogs, "all task logs")
+		fourthRow = append(fourthRow, keymap.KeyMap.AllTaskLogs)
 		fourthRow = append(fourthRow, keymap.KeyMap.Exec)
+		fourthRow = append(fourthRow, keymap.KeyMap.Export)
+		fourthRow = append(fourthRow, keymap.KeyMap.Coverage)
+		fourthRow = append(fourthRow, keymap.KeyMap.Ports)
+		fourthRow = append(fourthRow, keymap.KeyMap.Volumes)
+		fourthRow = append(fourthRow, keymap.KeyMap.Stats)
+		fourthRow = append(fourthRow, keymap.KeyMap.Checks)
+		fourthRow = append(fourthRow, keymap.KeyMap.Evaluations)
+		fourthRow = append(fourthRow, keymap.KeyMap.Deployment)
+		fourthRow = append(fourthRow, keymap.KeyMap.Rollout)
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyLogPath)
+		if !state.ReadOnly {
+			fourthRow = append(fourthRow, keymap.KeyMap.StopAlloc)
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.Collapse)
+		if state.TaskGroupsCollapsed {
+			changeKeyHelp(&keymap.KeyMap.CollapseAll, "expand all")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.CollapseAll, "collapse all")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.CollapseAll)
+	}
+
+	if currentPage == TaskEventsPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.ReverseOrder)
+	}
+
+	if currentPage == NodeSpecPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyID, keymap.KeyMap.CopyShortID)
+	}
+
+	if currentPage == ErrorsPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyError)
+		fourthRow = append(fourthRow, keymap.KeyMap.ClearErrors)
+	}
+
+	if currentPage == RawResponsesPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyBody)
+	}
+
+	if currentPage == AboutPage {
+		fourthRow = append(fourthRow, keymap.KeyMap.CopyValue)
+	}
+
+	if currentPage == JobEventsPage || currentPage == AllocEventsPage || currentPage == AllEventsPage {
+		if state.EventsPaused {
+			changeKeyHelp(&keymap.KeyMap.PauseEvents, "resume")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.PauseEvents, "pause")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.PauseEvents, keymap.KeyMap.CopyEvent, keymap.KeyMap.CopyEvents)
+		if state.MultipleEventJQQueries {
+			fourthRow = append(fourthRow, keymap.KeyMap.NextJQQuery)
+		}
+		if state.EventsFilterRegex {
+			changeKeyHelp(&keymap.KeyMap.RegexFilter, "text filter")
+		} else {
+			changeKeyHelp(&keymap.KeyMap.RegexFilter, "regex filter")
+		}
+		fourthRow = append(fourthRow, keymap.KeyMap.RegexFilter)
+	}
+
+	if currentPage == DispatchPage && state.EnteringInput {
+		changeKeyHelp(&keymap.KeyMap.Forward, "dispatch")
+		secondRow = []key.Binding{keymap.KeyMap.Back, keymap.KeyMap.Forward}
+		return [][]key.Binding{firstRow, secondRow}
 	}
 
 	if currentPage == ExecPage {
-		if enteringInput {
+		if state.EnteringInput {
 			changeKeyHelp(&keymap.KeyMap.Forward, "run command")
 			secondRow = append(fourthRow, keymap.KeyMap.Forward)
-			return getShortHelp(firstRow) + "\n" + getShortHelp(secondRow)
+			return [][]key.Binding{firstRow, secondRow}
 		}
-		if inPty {
+		if state.InPty {
 			changeKeyHelp(&keymap.KeyMap.Back, "disable input")
 			secondRow = []key.Binding{keymap.KeyMap.Back}
-			return getShortHelp(firstRow) + "\n" + getShortHelp(secondRow)
+			return [][]key.Binding{firstRow, secondRow}
 		} else {
-			if webSocketConnected {
+			if state.WebSocketConnected {
 				changeKeyHelp(&keymap.KeyMap.Forward, "enable input")
 				fourthRow = append(fourthRow, keymap.KeyMap.Forward)
 			}
 		}
 	}
 
-	if saving {
+	if state.Saving {
 		changeKeyHelp(&keymap.KeyMap.Forward, "confirm save")
 		changeKeyHelp(&keymap.KeyMap.Back, "cancel save")
 		secondRow = []key.Binding{keymap.KeyMap.Back, keymap.KeyMap.Forward}
-		return getShortHelp(firstRow) + "\n" + getShortHelp(secondRow)
+		return [][]key.Binding{firstRow, secondRow}
 	}
 
-	if filterFocused {
+	if state.FilterFocused {
 		changeKeyHelp(&keymap.KeyMap.Forward, "apply filter")
 		changeKeyHelp(&keymap.KeyMap.Back, "cancel filter")
 		secondRow = []key.Binding{keymap.KeyMap.Back, keymap.KeyMap.Forward}
-		return getShortHelp(firstRow) + "\n" + getShortHelp(secondRow)
+		return [][]key.Binding{firstRow, secondRow}
 	}
 
-	var final string
-	for _, row := range [][]key.Binding{firstRow, secondRow, thirdRow, fourthRow} {
-		final += getShortHelp(row) + "\n"
-	}
-
-	return strings.TrimRight(final, "\n")
+	return [][]key.Binding{firstRow, secondRow, thirdRow, fourthRow, state.FooterHints}
 }
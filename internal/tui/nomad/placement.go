@@ -0,0 +1,102 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"strings"
+)
+
+const placementIndent = "  "
+
+// FetchJobPlacement lists the constraints, affinities and spreads of the job, its task groups and their tasks
+func FetchJobPlacement(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{Namespace: jobNamespace})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		lines := []string{fmt.Sprintf("Job %s", jobID)}
+		lines = append(lines, placementRules(1, job.Constraints, job.Affinities, job.Spreads)...)
+		for _, tg := range job.TaskGroups {
+			lines = append(lines, "", fmt.Sprintf("Group %s", derefString(tg.Name)))
+			lines = append(lines, placementRules(1, tg.Constraints, tg.Affinities, tg.Spreads)...)
+			for _, t := range tg.Tasks {
+				if len(t.Constraints) == 0 && len(t.Affinities) == 0 {
+					continue
+				}
+				lines = append(lines, fmt.Sprintf("%sTask %s", placementIndent, t.Name))
+				lines = append(lines, placementRules(2, t.Constraints, t.Affinities, nil)...)
+			}
+		}
+
+		var rows []page.Row
+		for _, l := range lines {
+			rows = append(rows, page.Row{Row: l})
+		}
+		return PageLoadedMsg{Page: JobPlacementPage, TableHeader: []string{}, AllPageRows: rows}
+	}
+}
+
+func placementRules(depth int, constraints []*api.Constraint, affinities []*api.Affinity, spreads []*api.Spread) []string {
+	indent := strings.Repeat(placementIndent, depth)
+	if len(constraints) == 0 && len(affinities) == 0 && len(spreads) == 0 {
+		return []string{indent + "No constraints, affinities or spreads"}
+	}
+
+	var lines []string
+	if len(constraints) > 0 {
+		lines = append(lines, indent+"Constraints")
+		for _, c := range constraints {
+			lines = append(lines, indent+placementIndent+joinNonEmpty(c.LTarget, c.Operand, c.RTarget))
+		}
+	}
+	if len(affinities) > 0 {
+		lines = append(lines, indent+"Affinities")
+		for _, a := range affinities {
+			lines = append(lines, fmt.Sprintf("%s%s%s (weight %d)", indent, placementIndent, joinNonEmpty(a.LTarget, a.Operand, a.RTarget), derefInt8(a.Weight)))
+		}
+	}
+	if len(spreads) > 0 {
+		lines = append(lines, indent+"Spreads")
+		for _, s := range spreads {
+			var targets []string
+			for _, t := range s.SpreadTarget {
+				targets = append(targets, fmt.Sprintf("%s %d%%", t.Value, t.Percent))
+			}
+			line := fmt.Sprintf("%s%s%s (weight %d)", indent, placementIndent, s.Attribute, derefInt8(s.Weight))
+			if len(targets) > 0 {
+				line += ": " + strings.Join(targets, ", ")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func joinNonEmpty(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+func derefInt8(i *int8) int8 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
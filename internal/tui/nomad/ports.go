@@ -0,0 +1,115 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strconv"
+)
+
+type allocPort struct {
+	Scope, Mode, Label, Type, HostNetwork, HostIP string
+	Value, To                                     int
+}
+
+// FetchAllocPorts lists the ports allocated to the allocation's group network and, for older jobs, its tasks' networks
+func FetchAllocPorts(client api.Client, allocID string) tea.Cmd {
+	return func() tea.Msg {
+		alloc, _, err := client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var ports []allocPort
+		if r := alloc.AllocatedResources; r != nil {
+			ports = append(ports, networkPorts("group", r.Shared.Networks)...)
+			var taskNames []string
+			for name := range r.Tasks {
+				taskNames = append(taskNames, name)
+			}
+			sort.Strings(taskNames)
+			for _, name := range taskNames {
+				ports = append(ports, networkPorts("task "+name, r.Tasks[name].Networks)...)
+			}
+		}
+		if len(ports) == 0 {
+			return PageLoadedMsg{Page: AllocPortsPage, TableHeader: []string{"No ports allocated"}, AllPageRows: []page.Row{}}
+		}
+
+		var portRows [][]string
+		for _, p := range ports {
+			to := ""
+			if p.To != 0 {
+				to = strconv.Itoa(p.To)
+			}
+			portRows = append(portRows, []string{
+				p.Label,
+				p.Type,
+				fmt.Sprintf("%s:%d", p.HostIP, p.Value),
+				to,
+				p.HostNetwork,
+				p.Mode,
+				p.Scope,
+			})
+		}
+		columns := []string{"Label", "Type", "Host Address", "Mapped To", "Host Network", "Mode", "Scope"}
+		table := formatter.GetRenderedTableAsString(columns, portRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row, Fields: portFilterFields(ports[idx])})
+		}
+
+		return PageLoadedMsg{Page: AllocPortsPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func networkPorts(scope string, networks []*api.NetworkResource) []allocPort {
+	var ports []allocPort
+	for _, n := range networks {
+		if n == nil {
+			continue
+		}
+		for _, p := range n.ReservedPorts {
+			ports = append(ports, toAllocPort(scope, "static", n, p))
+		}
+		for _, p := range n.DynamicPorts {
+			ports = append(ports, toAllocPort(scope, "dynamic", n, p))
+		}
+	}
+	return ports
+}
+
+func toAllocPort(scope, portType string, n *api.NetworkResource, p api.Port) allocPort {
+	hostNetwork := p.HostNetwork
+	if hostNetwork == "" {
+		hostNetwork = "default"
+	}
+	mode := n.Mode
+	if mode == "" {
+		mode = "host"
+	}
+	return allocPort{
+		Scope:       scope,
+		Mode:        mode,
+		Label:       p.Label,
+		Type:        portType,
+		HostNetwork: hostNetwork,
+		HostIP:      n.IP,
+		Value:       p.Value,
+		To:          p.To,
+	}
+}
+
+func portFilterFields(p allocPort) map[string]string {
+	return map[string]string{
+		"label": p.Label,
+		"type":  p.Type,
+		"port":  strconv.Itoa(p.Value),
+		"scope": p.Scope,
+	}
+}
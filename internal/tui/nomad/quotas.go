@@ -0,0 +1,96 @@
+package nomad
+
+import (
+	"encoding/base64"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+)
+
+type QuotasCheckedMsg struct {
+	Available bool
+}
+
+// CheckQuotas checks whether the cluster has quotas, which are Nomad Enterprise only, and the token can read them
+func CheckQuotas(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.Quotas().List(nil)
+		return QuotasCheckedMsg{Available: err == nil}
+	}
+}
+
+// FetchQuotas shows the usage of each quota against its limits in each region. Usage is keyed by the hash of the limit
+// it counts against.
+func FetchQuotas(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		specs, _, err := client.Quotas().List(nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		usages, _, err := client.Quotas().ListUsage(nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		usageByName := make(map[string]*api.QuotaUsage)
+		for _, u := range usages {
+			usageByName[u.Name] = u
+		}
+
+		sort.Slice(specs, func(x, y int) bool {
+			return specs[x].Name < specs[y].Name
+		})
+
+		var quotaRows [][]string
+		for _, spec := range specs {
+			limits := spec.Limits
+			sort.Slice(limits, func(x, y int) bool {
+				return limits[x].Region < limits[y].Region
+			})
+			for _, limit := range limits {
+				var used *api.Resources
+				if u, exists := usageByName[spec.Name]; exists {
+					if l, exists := u.Used[base64.StdEncoding.EncodeToString(limit.Hash)]; exists {
+						used = l.RegionLimit
+					}
+				}
+				var usedCPU, usedMemory, limitCPU, limitMemory *int
+				if used != nil {
+					usedCPU, usedMemory = used.CPU, used.MemoryMB
+				}
+				if limit.RegionLimit != nil {
+					limitCPU, limitMemory = limit.RegionLimit.CPU, limit.RegionLimit.MemoryMB
+				}
+				quotaRows = append(quotaRows, []string{
+					spec.Name,
+					limit.Region,
+					quotaGraph(derefInt(usedCPU), derefInt(limitCPU), "MHz"),
+					quotaGraph(derefInt(usedMemory), derefInt(limitMemory), "MiB"),
+				})
+			}
+		}
+		columns := []string{"Quota", "Region", "CPU", "Memory"}
+		table := formatter.GetRenderedTableAsString(columns, quotaRows)
+
+		var rows []page.Row
+		for _, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row})
+		}
+
+		return PageLoadedMsg{Page: QuotasPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+// quotaGraph graphs used against limit. Quotas treat a limit of 0 as unlimited and a negative limit as none allowed.
+func quotaGraph(used, limit int, unit string) string {
+	switch {
+	case limit == 0:
+		return fmt.Sprintf("%d %s, unlimited", used, unit)
+	case limit < 0:
+		return "none allowed"
+	}
+	return usageGraph(int64(used), int64(limit), unit)
+}
@@ -0,0 +1,56 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/style"
+	"strings"
+	"time"
+)
+
+// RawResponse is the body of an api response as Nomad returned it, captured to debug what wander shows
+type RawResponse struct {
+	Time      time.Time
+	Method    string
+	URL       string
+	Status    int
+	Body      string
+	Truncated bool
+}
+
+// ListRawResponses shows the captured responses newest first, each a summary line followed by its body, pretty-printed
+// if it's JSON. Each row's key is its response's body, to copy.
+func ListRawResponses(responses []RawResponse, capturing bool) tea.Cmd {
+	return func() tea.Msg {
+		if !capturing {
+			tableHeader := []string{"Responses aren't captured. Start wander with --capture-responses to capture them."}
+			return PageLoadedMsg{Page: RawResponsesPage, TableHeader: tableHeader, AllPageRows: []page.Row{}}
+		}
+		if len(responses) == 0 {
+			return PageLoadedMsg{Page: RawResponsesPage, TableHeader: []string{"No responses captured yet"}, AllPageRows: []page.Row{}}
+		}
+
+		var rows []page.Row
+		for i := len(responses) - 1; i >= 0; i-- {
+			r := responses[i]
+			summary := fmt.Sprintf("%s %s %s %d", formatter.FormatTime(r.Time), r.Method, r.URL, r.Status)
+			if r.Truncated {
+				summary += " (truncated)"
+			}
+			rows = append(rows, page.Row{Key: r.Body, Row: summary, Style: &style.Bold})
+
+			lines := formatter.PrettyJsonStringAsLines(r.Body)
+			if len(lines) == 1 {
+				// not JSON, e.g. truncated or an error message
+				lines = strings.Split(strings.TrimRight(r.Body, "\n"), "\n")
+			}
+			for _, l := range lines {
+				rows = append(rows, page.Row{Key: r.Body, Row: l})
+			}
+			rows = append(rows, page.Row{Key: r.Body, Row: ""})
+		}
+		return PageLoadedMsg{Page: RawResponsesPage, TableHeader: []string{}, AllPageRows: rows}
+	}
+}
@@ -0,0 +1,145 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"github.com/robinovitch61/wander/internal/tui/style"
+	"sort"
+	"strconv"
+)
+
+const (
+	rolloutHealthy   = "healthy"
+	rolloutUnhealthy = "unhealthy"
+	rolloutFailed    = "failed"
+	rolloutPending   = "pending"
+)
+
+// FetchJobRollout lists the allocations of the job's latest deployment, or of its latest version if it has none, with
+// their health, newest first. Healthy allocations are green and unhealthy or failed ones red.
+func FetchJobRollout(client api.Client, jobID, jobNamespace string) tea.Cmd {
+	return func() tea.Msg {
+		q := &api.QueryOptions{Namespace: jobNamespace}
+		deployment, _, err := client.Jobs().LatestDeployment(jobID, q)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var allocs []*api.AllocationListStub
+		var summary string
+		if deployment != nil {
+			allocs, _, err = client.Deployments().Allocations(deployment.ID, q)
+			if err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			summary = fmt.Sprintf("Deployment %s of version %d is %s", formatter.ShortAllocID(deployment.ID), deployment.JobVersion, deployment.Status)
+		} else {
+			jobAllocs, _, err := client.Jobs().Allocations(jobID, false, q)
+			if err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			var latestVersion uint64
+			for _, alloc := range jobAllocs {
+				if alloc.JobVersion > latestVersion {
+					latestVersion = alloc.JobVersion
+				}
+			}
+			for _, alloc := range jobAllocs {
+				if alloc.JobVersion == latestVersion {
+					allocs = append(allocs, alloc)
+				}
+			}
+			summary = fmt.Sprintf("No deployments, showing allocations of version %d", latestVersion)
+		}
+		if len(allocs) == 0 {
+			return PageLoadedMsg{Page: JobRolloutPage, TableHeader: []string{summary, "No allocations yet"}, AllPageRows: []page.Row{}}
+		}
+		sort.Slice(allocs, func(x, y int) bool {
+			if allocs[x].CreateTime == allocs[y].CreateTime {
+				return allocs[x].ID < allocs[y].ID
+			}
+			return allocs[x].CreateTime > allocs[y].CreateTime
+		})
+
+		var healthy int
+		var healths []string
+		var allocRows [][]string
+		for _, alloc := range allocs {
+			health := rolloutHealth(alloc)
+			if health == rolloutHealthy {
+				healthy += 1
+			}
+			healths = append(healths, health)
+			allocRows = append(allocRows, []string{
+				formatter.ShortAllocID(alloc.ID),
+				alloc.Name,
+				alloc.NodeName,
+				strconv.FormatUint(alloc.JobVersion, 10),
+				alloc.ClientStatus,
+				health,
+				formatter.FormatTimeNs(alloc.CreateTime),
+			})
+		}
+		columns := []string{"Alloc ID", "Alloc Name", "Node", "Version", "Status", "Health", "Created"}
+		table := formatter.GetRenderedTableAsString(columns, allocRows)
+
+		tableHeader := []string{fmt.Sprintf("%s, %d of %d allocations healthy", summary, healthy, len(allocs)), ""}
+		tableHeader = append(tableHeader, table.HeaderRows...)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			health := healths[idx]
+			r := page.Row{Key: allocs[idx].ID, Row: row, Fields: map[string]string{
+				"id":     allocs[idx].ID,
+				"name":   allocs[idx].Name,
+				"status": allocs[idx].ClientStatus,
+				"health": health,
+			}}
+			switch health {
+			case rolloutHealthy:
+				r.Style = &style.RolloutHealthy
+			case rolloutUnhealthy, rolloutFailed:
+				r.Style = &style.RolloutUnhealthy
+			}
+			rows = append(rows, r)
+		}
+
+		return PageLoadedMsg{Page: JobRolloutPage, TableHeader: tableHeader, AllPageRows: rows}
+	}
+}
+
+func rolloutHealth(alloc *api.AllocationListStub) string {
+	if alloc.ClientStatus == "failed" || alloc.ClientStatus == "lost" {
+		return rolloutFailed
+	}
+	if alloc.DeploymentStatus == nil || alloc.DeploymentStatus.Healthy == nil {
+		return rolloutPending
+	}
+	if *alloc.DeploymentStatus.Healthy {
+		return rolloutHealthy
+	}
+	return rolloutUnhealthy
+}
+
+// RolloutTransitions compares the health of the rollout page's rows to their previous health by allocation ID,
+// describing allocations that became healthy, unhealthy or failed. allHealthy is true once every allocation is.
+func RolloutTransitions(previous map[string]string, rows []page.Row) (current map[string]string, transitions []string, allHealthy bool) {
+	current = make(map[string]string)
+	allHealthy = len(rows) > 0
+	for _, r := range rows {
+		health := r.Fields["health"]
+		current[r.Key] = health
+		if health != rolloutHealthy {
+			allHealthy = false
+		}
+		if prev, exists := previous[r.Key]; exists && prev != health && health != rolloutPending {
+			transitions = append(transitions, fmt.Sprintf("%s %s", formatter.ShortAllocID(r.Key), health))
+		}
+	}
+	sort.Strings(transitions)
+	return current, transitions, allHealthy
+}
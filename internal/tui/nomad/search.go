@@ -0,0 +1,207 @@
+package nomad
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"strings"
+	"time"
+)
+
+type SearchResultType string
+
+const (
+	JobSearchResult   SearchResultType = "job"
+	AllocSearchResult SearchResultType = "allocation"
+	NodeSearchResult  SearchResultType = "node"
+)
+
+type SearchDebounceMsg struct {
+	ID    int
+	Query string
+}
+
+func SearchWithDelay(id int, query string) tea.Cmd {
+	return tea.Tick(constants.SearchDebounceDuration, func(t time.Time) tea.Msg { return SearchDebounceMsg{id, query} })
+}
+
+// FetchSearchResults finds jobs, allocations, and nodes with IDs starting with query, then fills each category up to
+// its cap with those whose names contain query. Substring matches use Nomad's fuzzy search, so are skipped on clusters
+// where it's unavailable or disabled. The substring matches of each category are looked up in one filtered list call.
+func FetchSearchResults(client api.Client, query string) tea.Cmd {
+	return func() tea.Msg {
+		if query == "" {
+			return PageLoadedMsg{Page: SearchPage, TableHeader: []string{}, AllPageRows: []page.Row{}}
+		}
+
+		var (
+			results [][]string
+			keys    []string
+			errs    []error
+		)
+		q := &api.QueryOptions{Prefix: query}
+
+		var substringMatches map[contexts.Context][]api.FuzzyMatch
+		if fuzzy, _, err := client.Search().FuzzySearch(query, contexts.All, nil); err == nil {
+			substringMatches = fuzzy.Matches
+		}
+		found := make(map[string]bool)
+
+		jobs, _, err := client.Jobs().PrefixList(query)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, j := range jobs[:min(len(jobs), constants.SearchResultsPerCategory)] {
+			results = append(results, []string{string(JobSearchResult), j.ID, j.Name, j.Namespace, j.Status})
+			keys = append(keys, toSearchKey(JobSearchResult, toJobsKey(j)))
+			found[JobKey(j.ID, j.Namespace)] = true
+		}
+		jobCount := len(jobs[:min(len(jobs), constants.SearchResultsPerCategory)])
+		// a job's scope is its namespace and ID, as matches are on its name
+		wanted, filters := make(map[string]bool), []string{}
+		for _, match := range substringMatches[contexts.Jobs] {
+			if jobCount+len(wanted) >= constants.SearchResultsPerCategory {
+				break
+			}
+			if len(match.Scope) < 2 || found[JobKey(match.Scope[1], match.Scope[0])] {
+				continue
+			}
+			wanted[JobKey(match.Scope[1], match.Scope[0])] = true
+			filters = append(filters, fmt.Sprintf("(Namespace == %q and ID == %q)", match.Scope[0], match.Scope[1]))
+		}
+		if len(filters) > 0 {
+			matchedJobs, _, err := client.Jobs().List(&api.QueryOptions{Namespace: "*", Filter: strings.Join(filters, " or ")})
+			if err == nil {
+				for _, j := range matchedJobs {
+					if !wanted[JobKey(j.ID, j.Namespace)] {
+						continue
+					}
+					results = append(results, []string{string(JobSearchResult), j.ID, j.Name, j.Namespace, j.Status})
+					keys = append(keys, toSearchKey(JobSearchResult, toJobsKey(j)))
+				}
+			}
+		}
+
+		allocs, _, err := client.Allocations().List(q)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, a := range allocs[:min(len(allocs), constants.SearchResultsPerCategory)] {
+			allocAsJSON, err := json.Marshal(a)
+			if err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			results = append(results, []string{string(AllocSearchResult), a.ID, a.Name, a.Namespace, a.ClientStatus})
+			keys = append(keys, toSearchKey(AllocSearchResult, string(allocAsJSON)))
+			found[a.ID] = true
+		}
+		allocCount := len(allocs[:min(len(allocs), constants.SearchResultsPerCategory)])
+		// an alloc's scope ends with its ID, as matches are on its name
+		wanted, filters = make(map[string]bool), []string{}
+		for _, match := range substringMatches[contexts.Allocs] {
+			if allocCount+len(wanted) >= constants.SearchResultsPerCategory {
+				break
+			}
+			if len(match.Scope) == 0 || found[match.Scope[len(match.Scope)-1]] {
+				continue
+			}
+			wanted[match.Scope[len(match.Scope)-1]] = true
+			filters = append(filters, fmt.Sprintf("ID == %q", match.Scope[len(match.Scope)-1]))
+		}
+		if len(filters) > 0 {
+			matchedAllocs, _, err := client.Allocations().List(&api.QueryOptions{Namespace: "*", Filter: strings.Join(filters, " or ")})
+			if err == nil {
+				for _, a := range matchedAllocs {
+					if !wanted[a.ID] {
+						continue
+					}
+					allocAsJSON, err := json.Marshal(a)
+					if err != nil {
+						return message.ErrMsg{Err: err}
+					}
+					results = append(results, []string{string(AllocSearchResult), a.ID, a.Name, a.Namespace, a.ClientStatus})
+					keys = append(keys, toSearchKey(AllocSearchResult, string(allocAsJSON)))
+				}
+			}
+		}
+
+		nodes, _, err := client.Nodes().List(q)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, n := range nodes[:min(len(nodes), constants.SearchResultsPerCategory)] {
+			results = append(results, []string{string(NodeSearchResult), n.ID, n.Name, "-", n.Status})
+			keys = append(keys, toSearchKey(NodeSearchResult, n.ID))
+			found[n.ID] = true
+		}
+		nodeCount := len(nodes[:min(len(nodes), constants.SearchResultsPerCategory)])
+		// a node's scope is its ID, as matches are on its name
+		wanted, filters = make(map[string]bool), []string{}
+		for _, match := range substringMatches[contexts.Nodes] {
+			if nodeCount+len(wanted) >= constants.SearchResultsPerCategory {
+				break
+			}
+			if len(match.Scope) == 0 || found[match.Scope[0]] {
+				continue
+			}
+			wanted[match.Scope[0]] = true
+			filters = append(filters, fmt.Sprintf("ID == %q", match.Scope[0]))
+		}
+		if len(filters) > 0 {
+			matchedNodes, _, err := client.Nodes().List(&api.QueryOptions{Filter: strings.Join(filters, " or ")})
+			if err == nil {
+				for _, n := range matchedNodes {
+					if !wanted[n.ID] {
+						continue
+					}
+					results = append(results, []string{string(NodeSearchResult), n.ID, n.Name, "-", n.Status})
+					keys = append(keys, toSearchKey(NodeSearchResult, n.ID))
+				}
+			}
+		}
+
+		if len(errs) == 3 {
+			return message.ToastMsg{Err: fmt.Errorf("search failed: %w", errs[0])}
+		}
+
+		if len(results) == 0 {
+			return PageLoadedMsg{Page: SearchPage, TableHeader: []string{}, AllPageRows: []page.Row{}}
+		}
+
+		columns := []string{"Type", "ID", "Name", "Namespace", "Status"}
+		table := formatter.GetRenderedTableAsString(columns, results)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		}
+		return PageLoadedMsg{Page: SearchPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func toSearchKey(resultType SearchResultType, value string) string {
+	return string(resultType) + keySeparator + value
+}
+
+// SearchResultFromKey returns the type of search result and the key for its corresponding page
+func SearchResultFromKey(key string) (SearchResultType, string, error) {
+	split := strings.SplitN(key, keySeparator, 2)
+	if len(split) != 2 {
+		return "", "", errors.New("invalid search result")
+	}
+	return SearchResultType(split[0]), split[1], nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,80 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strconv"
+)
+
+// FetchServers lists the server members of the cluster, flagging each region's leader the same way the nomad CLI does,
+// by comparing a member's RPC address to the region's leader address
+func FetchServers(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		members, err := client.Agent().Members()
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		servers := members.Members
+		sort.Slice(servers, func(x, y int) bool {
+			if servers[x].Tags["region"] == servers[y].Tags["region"] {
+				return servers[x].Name < servers[y].Name
+			}
+			return servers[x].Tags["region"] < servers[y].Tags["region"]
+		})
+
+		leaders := make(map[string]string)
+		for _, s := range servers {
+			region := s.Tags["region"]
+			if _, exists := leaders[region]; exists {
+				continue
+			}
+			// a region without a leader, or one that can't be reached, shows no leader rather than failing the page
+			leader, _ := client.Status().RegionLeader(region)
+			leaders[region] = leader
+		}
+
+		var serverRows [][]string
+		for _, s := range servers {
+			region := s.Tags["region"]
+			isLeader := leaders[region] != "" && leaders[region] == fmt.Sprintf("%s:%s", s.Addr, s.Tags["port"])
+			serverRows = append(serverRows, []string{
+				s.Name,
+				fmt.Sprintf("%s:%d", s.Addr, s.Port),
+				s.Status,
+				strconv.FormatBool(isLeader),
+				orDash(s.Tags["raft_vsn"]),
+				orDash(region),
+				orDash(s.Tags["dc"]),
+				orDash(s.Tags["build"]),
+			})
+		}
+		columns := []string{"Name", "Address", "Status", "Leader", "Raft Version", "Region", "Datacenter", "Build"}
+		table := formatter.GetRenderedTableAsString(columns, serverRows)
+
+		summary := fmt.Sprintf("%d servers in %d regions", len(servers), len(leaders))
+		var leaderless int
+		for _, leader := range leaders {
+			if leader == "" {
+				leaderless += 1
+			}
+		}
+		if leaderless > 0 {
+			summary += fmt.Sprintf(", %d without a reachable leader", leaderless)
+		}
+		tableHeader := []string{summary, ""}
+		tableHeader = append(tableHeader, table.HeaderRows...)
+
+		var rows []page.Row
+		for _, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row})
+		}
+
+		return PageLoadedMsg{Page: ServersPage, TableHeader: tableHeader, AllPageRows: rows}
+	}
+}
@@ -0,0 +1,128 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"math"
+	"sort"
+	"strconv"
+)
+
+type taskStat struct {
+	Task, Label, Value string
+}
+
+// FetchAllocStats lists the resource usage the allocation's task drivers report. Fields are read generically so
+// whatever a driver measures is shown, and unmeasured, zero or empty ones are hidden.
+func FetchAllocStats(client api.Client, allocID string) tea.Cmd {
+	return func() tea.Msg {
+		alloc, _, err := client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		if alloc.ClientStatus != "running" {
+			tableHeader := []string{fmt.Sprintf("Stats are only reported for running allocations, and this one is %s", alloc.ClientStatus)}
+			return PageLoadedMsg{Page: AllocStatsPage, TableHeader: tableHeader, AllPageRows: []page.Row{}}
+		}
+
+		usage, err := client.Allocations().Stats(alloc, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var taskNames []string
+		for name := range usage.Tasks {
+			taskNames = append(taskNames, name)
+		}
+		sort.Strings(taskNames)
+
+		var stats []taskStat
+		for _, name := range taskNames {
+			taskUsage := usage.Tasks[name]
+			if taskUsage == nil || taskUsage.ResourceUsage == nil {
+				continue
+			}
+			b, err := json.Marshal(taskUsage.ResourceUsage)
+			if err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			var fields map[string]interface{}
+			if err = json.Unmarshal(b, &fields); err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			labeled := make(map[string]string)
+			flattenStats("", fields, labeled)
+			var labels []string
+			for label := range labeled {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+			for _, label := range labels {
+				stats = append(stats, taskStat{Task: name, Label: label, Value: labeled[label]})
+			}
+		}
+		if len(stats) == 0 {
+			return PageLoadedMsg{Page: AllocStatsPage, TableHeader: []string{"No stats reported"}, AllPageRows: []page.Row{}}
+		}
+
+		var statRows [][]string
+		for _, s := range stats {
+			statRows = append(statRows, []string{s.Task, s.Label, s.Value})
+		}
+		columns := []string{"Task", "Stat", "Value"}
+		table := formatter.GetRenderedTableAsString(columns, statRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row, Fields: map[string]string{"task": stats[idx].Task, "stat": stats[idx].Label}})
+		}
+
+		return PageLoadedMsg{Page: AllocStatsPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+// flattenStats adds each non-zero leaf of fields to labeled, keyed by its dotted path. Measured lists which fields a
+// driver reports rather than a value, so it is skipped.
+func flattenStats(prefix string, fields map[string]interface{}, labeled map[string]string) {
+	for k, v := range fields {
+		if k == "Measured" {
+			continue
+		}
+		label := k
+		if prefix != "" {
+			label = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flattenStats(label, v, labeled)
+		case float64:
+			if v == 0 {
+				continue
+			}
+			if v == math.Trunc(v) {
+				labeled[label] = strconv.FormatFloat(v, 'f', 0, 64)
+			} else {
+				labeled[label] = strconv.FormatFloat(v, 'f', 2, 64)
+			}
+		case string:
+			if v != "" {
+				labeled[label] = v
+			}
+		case bool:
+			if v {
+				labeled[label] = "true"
+			}
+		case []interface{}:
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					flattenStats(fmt.Sprintf("%s[%d]", label, i), m, labeled)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strings"
+)
+
+func FetchTaskEvents(client api.Client, allocID, taskName string, oldestFirst bool) tea.Cmd {
+	return func() tea.Msg {
+		alloc, _, err := client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var events []*api.TaskEvent
+		if taskState, exists := alloc.TaskStates[taskName]; exists {
+			events = taskState.Events
+		}
+
+		sort.SliceStable(events, func(x, y int) bool {
+			if oldestFirst {
+				return events[x].Time < events[y].Time
+			}
+			return events[x].Time > events[y].Time
+		})
+
+		var eventRows [][]string
+		var keys []string
+		for _, e := range events {
+			// the full event is the key so the message can be expanded on selection
+			eventAsJSON, err := json.Marshal(e)
+			if err != nil {
+				return message.ErrMsg{Err: err}
+			}
+			eventRows = append(eventRows, []string{formatter.FormatTimeNs(e.Time), e.Type, taskEventMessage(*e)})
+			keys = append(keys, string(eventAsJSON))
+		}
+
+		columns := []string{"Time", "Type", "Message"}
+		table := formatter.GetRenderedTableAsString(columns, eventRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: keys[idx], Row: row})
+		}
+
+		return PageLoadedMsg{Page: TaskEventsPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+// taskEventMessage is the single line message of a task event, preferring the one Nomad intends to display
+func taskEventMessage(e api.TaskEvent) string {
+	message := e.DisplayMessage
+	for _, m := range []string{e.Message, e.DriverError, e.KillError, e.SetupError} {
+		if message == "" {
+			message = m
+		}
+	}
+	if message == "" && e.ExitCode != 0 {
+		message = fmt.Sprintf("Exit code %d", e.ExitCode)
+	}
+	return strings.Join(strings.Fields(message), " ")
+}
@@ -0,0 +1,139 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/constants"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jobUsage is the resources allocated to the running allocations of a job
+type jobUsage struct {
+	ID, Namespace     string
+	Allocs            int
+	CPUMHz, MemoryMiB int64
+}
+
+// FetchTop aggregates the CPU and memory allocated to running allocations against what ready nodes have available,
+// i.e. reserved resources rather than actual utilization, which would require querying every client
+func FetchTop(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		// listing with resources includes what would otherwise need an info request per node and allocation
+		withResources := map[string]string{"resources": "true"}
+		nodes, _, err := client.Nodes().List(&api.QueryOptions{Params: withResources})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+		allocs, _, err := client.Allocations().List(&api.QueryOptions{Namespace: "*", Params: withResources})
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var availableCPU, availableMemory, allocatedCPU, allocatedMemory int64
+		readyNodes := make(map[string]bool)
+		for _, node := range nodes {
+			if node.Status != "ready" {
+				continue
+			}
+			readyNodes[node.ID] = true
+			if r := node.NodeResources; r != nil {
+				availableCPU += r.Cpu.CpuShares
+				availableMemory += r.Memory.MemoryMB
+			}
+			if r := node.ReservedResources; r != nil {
+				availableCPU -= int64(r.Cpu.CpuShares)
+				availableMemory -= int64(r.Memory.MemoryMB)
+			}
+		}
+
+		usageByJob := make(map[string]*jobUsage)
+		for _, alloc := range allocs {
+			if !readyNodes[alloc.NodeID] || alloc.ClientStatus != "running" || alloc.AllocatedResources == nil {
+				continue
+			}
+			key := alloc.Namespace + keySeparator + alloc.JobID
+			usage, exists := usageByJob[key]
+			if !exists {
+				usage = &jobUsage{ID: alloc.JobID, Namespace: alloc.Namespace}
+				usageByJob[key] = usage
+			}
+			usage.Allocs += 1
+			for _, task := range alloc.AllocatedResources.Tasks {
+				usage.CPUMHz += task.Cpu.CpuShares
+				usage.MemoryMiB += task.Memory.MemoryMB
+			}
+		}
+
+		var usages []jobUsage
+		for _, usage := range usageByJob {
+			allocatedCPU += usage.CPUMHz
+			allocatedMemory += usage.MemoryMiB
+			usages = append(usages, *usage)
+		}
+		sort.Slice(usages, func(x, y int) bool {
+			if usages[x].CPUMHz == usages[y].CPUMHz {
+				if usages[x].MemoryMiB == usages[y].MemoryMiB {
+					return usages[x].ID < usages[y].ID
+				}
+				return usages[x].MemoryMiB > usages[y].MemoryMiB
+			}
+			return usages[x].CPUMHz > usages[y].CPUMHz
+		})
+		if len(usages) > constants.TopJobsCount {
+			usages = usages[:constants.TopJobsCount]
+		}
+
+		var jobRows [][]string
+		for _, u := range usages {
+			jobRows = append(jobRows, []string{
+				u.ID,
+				u.Namespace,
+				strconv.Itoa(u.Allocs),
+				strconv.FormatInt(u.CPUMHz, 10),
+				strconv.FormatInt(u.MemoryMiB, 10),
+			})
+		}
+		columns := []string{"Job", "Namespace", "Running Allocs", "CPU (MHz)", "Memory (MiB)"}
+		table := formatter.GetRenderedTableAsString(columns, jobRows)
+
+		// the summary is part of the header so it stays visible while scrolling
+		tableHeader := []string{
+			usageBar("CPU", allocatedCPU, availableCPU, "MHz"),
+			usageBar("Memory", allocatedMemory, availableMemory, "MiB"),
+			"",
+			fmt.Sprintf("Top %d jobs by allocated resources", constants.TopJobsCount),
+		}
+		tableHeader = append(tableHeader, table.HeaderRows...)
+
+		var rows []page.Row
+		for _, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row})
+		}
+
+		return PageLoadedMsg{Page: TopPage, TableHeader: tableHeader, AllPageRows: rows}
+	}
+}
+
+func usageBar(label string, used, available int64, unit string) string {
+	return fmt.Sprintf("%-8s %s", label, usageGraph(used, available, unit))
+}
+
+// usageGraph is an inline bar graph of used out of available
+func usageGraph(used, available int64, unit string) string {
+	var fraction float64
+	if available > 0 {
+		fraction = float64(used) / float64(available)
+	}
+	filled := int(fraction * float64(constants.TopBarWidth))
+	if filled > constants.TopBarWidth {
+		filled = constants.TopBarWidth
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", constants.TopBarWidth-filled)
+	return fmt.Sprintf("[%s] %3.0f%%  %d/%d %s", bar, fraction*100, used, available, unit)
+}
@@ -0,0 +1,73 @@
+package nomad
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"strconv"
+	"strings"
+)
+
+// ServerVersionMsg has the version of the Nomad agent wander is connected to, empty if it couldn't be detected
+type ServerVersionMsg struct {
+	Version string
+}
+
+// pageMinVersions are the Nomad versions that added the endpoints pages need, as major, minor
+var pageMinVersions = map[Page][2]int{
+	AllocChecksPage: {1, 4},
+}
+
+// FetchServerVersion detects the agent's version. Reading the agent requires agent:read, so without it the version is
+// unknown and every page is assumed supported.
+func FetchServerVersion(client api.Client) tea.Cmd {
+	return func() tea.Msg {
+		self, err := client.Agent().Self()
+		if err != nil {
+			return ServerVersionMsg{}
+		}
+		return ServerVersionMsg{Version: self.Member.Tags["build"]}
+	}
+}
+
+// UnsupportedPageNote explains that the page needs a newer Nomad than version, if it does
+func UnsupportedPageNote(p Page, version string) (string, bool) {
+	required, ok := pageMinVersions[p]
+	if !ok {
+		return "", false
+	}
+	major, minor, ok := parseMajorMinor(version)
+	if !ok || major > required[0] || (major == required[0] && minor >= required[1]) {
+		return "", false
+	}
+	return fmt.Sprintf("The %s page requires Nomad %d.%d or later, and this cluster runs %s", p, required[0], required[1], version), true
+}
+
+// LoadUnsupportedPage shows the note in place of the page's content
+func LoadUnsupportedPage(p Page, note string) tea.Cmd {
+	return func() tea.Msg {
+		return PageLoadedMsg{Page: p, TableHeader: []string{note}, AllPageRows: []page.Row{}}
+	}
+}
+
+// parseMajorMinor parses versions like 1.6.1, 1.4.0-beta.1 or 1.5.3+ent
+func parseMajorMinor(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minorDigits := strings.IndexFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+	if minorDigits == -1 {
+		minorDigits = len(parts[1])
+	}
+	minor, err := strconv.Atoi(parts[1][:minorDigits])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,101 @@
+package nomad
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+	"github.com/robinovitch61/wander/internal/tui/components/page"
+	"github.com/robinovitch61/wander/internal/tui/formatter"
+	"github.com/robinovitch61/wander/internal/tui/message"
+	"sort"
+	"strconv"
+)
+
+type allocVolumeMount struct {
+	Task, Volume, Type, Source, MountPath string
+	ReadOnly                              bool
+}
+
+// FetchAllocVolumes lists the host and CSI volumes the allocation's group requests and where each task mounts them.
+// Volumes no task mounts are listed without a task.
+func FetchAllocVolumes(client api.Client, allocID string) tea.Cmd {
+	return func() tea.Msg {
+		alloc, _, err := client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return message.ErrMsg{Err: err}
+		}
+
+		var group *api.TaskGroup
+		if alloc.Job != nil {
+			for _, tg := range alloc.Job.TaskGroups {
+				if derefString(tg.Name) == alloc.TaskGroup {
+					group = tg
+					break
+				}
+			}
+		}
+		if group == nil || len(group.Volumes) == 0 {
+			return PageLoadedMsg{Page: AllocVolumesPage, TableHeader: []string{"No volumes requested"}, AllPageRows: []page.Row{}}
+		}
+
+		var mounts []allocVolumeMount
+		mounted := make(map[string]bool)
+		for _, task := range group.Tasks {
+			for _, vm := range task.VolumeMounts {
+				if vm == nil {
+					continue
+				}
+				name := derefString(vm.Volume)
+				mount := allocVolumeMount{Task: task.Name, Volume: name, MountPath: derefString(vm.Destination), ReadOnly: derefBool(vm.ReadOnly)}
+				if req, exists := group.Volumes[name]; exists && req != nil {
+					mount.Type, mount.Source = req.Type, req.Source
+					mount.ReadOnly = mount.ReadOnly || req.ReadOnly
+				}
+				mounts = append(mounts, mount)
+				mounted[name] = true
+			}
+		}
+		for name, req := range group.Volumes {
+			if mounted[name] || req == nil {
+				continue
+			}
+			mounts = append(mounts, allocVolumeMount{Volume: name, Type: req.Type, Source: req.Source, ReadOnly: req.ReadOnly})
+		}
+		sort.SliceStable(mounts, func(x, y int) bool {
+			if mounts[x].Volume != mounts[y].Volume {
+				return mounts[x].Volume < mounts[y].Volume
+			}
+			return mounts[x].Task < mounts[y].Task
+		})
+
+		var mountRows [][]string
+		for _, m := range mounts {
+			mountRows = append(mountRows, []string{
+				m.Volume,
+				m.Type,
+				m.Source,
+				strconv.FormatBool(m.ReadOnly),
+				orDash(m.Task),
+				orDash(m.MountPath),
+			})
+		}
+		columns := []string{"Volume", "Type", "Source", "Read Only", "Task", "Mount Path"}
+		table := formatter.GetRenderedTableAsString(columns, mountRows)
+
+		var rows []page.Row
+		for idx, row := range table.ContentRows {
+			rows = append(rows, page.Row{Key: "", Row: row, Fields: volumeFilterFields(mounts[idx])})
+		}
+
+		return PageLoadedMsg{Page: AllocVolumesPage, TableHeader: table.HeaderRows, AllPageRows: rows}
+	}
+}
+
+func volumeFilterFields(m allocVolumeMount) map[string]string {
+	return map[string]string{
+		"volume": m.Volume,
+		"type":   m.Type,
+		"source": m.Source,
+		"task":   m.Task,
+		"mount":  m.MountPath,
+	}
+}
@@ -0,0 +1,53 @@
+package nomad
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/nomad/api"
+)
+
+// MissingJobStatus is the status of a watched job that doesn't exist, e.g. after it is purged
+const MissingJobStatus = "missing"
+
+// JobState is what is compared between checks of a watched job
+type JobState struct {
+	Status string
+	Failed int
+}
+
+// JobStatesMsg has the state of each job checked by key, leaving out jobs that couldn't be checked
+type JobStatesMsg struct {
+	States map[string]JobState
+}
+
+func JobKey(jobID, jobNamespace string) string {
+	return jobID + " " + jobNamespace
+}
+
+// FetchJobStates gets the status and total failed allocations of each job by key, as a job with failing allocations
+// can stay running
+func FetchJobStates(client api.Client, keys []string) tea.Cmd {
+	return func() tea.Msg {
+		states := make(map[string]JobState)
+		for _, key := range keys {
+			jobID, jobNamespace := JobIDAndNamespaceFromKey(key)
+			jobs, _, err := client.Jobs().List(&api.QueryOptions{Namespace: jobNamespace, Prefix: jobID})
+			if err != nil {
+				continue
+			}
+			state := JobState{Status: MissingJobStatus}
+			for _, j := range jobs {
+				if j.ID != jobID {
+					continue
+				}
+				state.Status = j.Status
+				if j.JobSummary != nil {
+					for _, s := range j.JobSummary.Summary {
+						state.Failed += s.Failed
+					}
+				}
+			}
+			states[key] = state
+		}
+		return JobStatesMsg{States: states}
+	}
+}
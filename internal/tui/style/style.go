@@ -26,6 +26,7 @@ var (
 	FilterPrefix               = Regular.Copy().Padding(0, 3).Border(lipgloss.NormalBorder(), true)
 	FilterEditing              = Regular.Copy().Foreground(black).Background(blue)
 	FilterApplied              = Regular.Copy().Foreground(black).Background(greenblue)
+	FilterError                = Regular.Copy().MarginLeft(1).Foreground(red)
 	JobRowPending              = Regular.Copy().Foreground(yellow)
 	JobRowDead                 = Regular.Copy().Foreground(red)
 	PseudoPrompt               = Regular.Copy().Background(blue)
@@ -34,11 +35,20 @@ var (
 	ViewportSelectedRowStyle   = Regular.Copy().Foreground(black).Background(blue)
 	ViewportHighlightStyle     = Regular.Copy().Foreground(black).Background(pink)
 	ViewportFooterStyle        = Regular.Copy().Foreground(grey)
+	ViewportPinnedStyle        = Regular.Copy().Foreground(black).Background(yellow)
 	SaveDialogPromptStyle      = Regular.Copy().Background(darkred).Foreground(black)
 	SaveDialogPlaceholderStyle = Regular.Copy().Background(darkred).Foreground(black)
 	SaveDialogTextStyle        = Regular.Copy().Background(darkred).Foreground(black)
 	StdOut                     = Regular.Copy().UnsetForeground()
 	StdErr                     = Regular.Copy().Foreground(red)
+	JSONLog                    = Regular.Copy().Foreground(greenblue)
+	CoverageMissing            = Regular.Copy().Foreground(red)
+	RolloutHealthy             = Regular.Copy().Foreground(darkgreen)
+	RolloutUnhealthy           = Regular.Copy().Foreground(red)
 	SuccessToast               = Bold.Copy().PaddingLeft(1).Foreground(black).Background(darkgreen)
 	ErrorToast                 = Bold.Copy().PaddingLeft(1).Foreground(black).Background(darkred)
+	ConfirmPrompt              = Bold.Copy().PaddingLeft(1).Foreground(black).Background(yellow)
+	Warning                    = Regular.Copy().PaddingLeft(1).Foreground(black).Background(yellow)
+	LastErrorPanel             = Regular.Copy().PaddingLeft(1).Foreground(black).Background(red)
+	Dimmed                     = Regular.Copy().Foreground(grey).Faint(true)
 )